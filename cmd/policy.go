@@ -0,0 +1,140 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/idebeijer/kube-mcp-server/pkg/policy"
+	"github.com/idebeijer/kube-mcp-server/pkg/tool"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+// policyTestCall is a sample tool call for "policy test". Namespace, resource
+// and verb are derived from the tool and its arguments like the server does,
+// unless they are set.
+type policyTestCall struct {
+	Name      string                 `json:"name"`
+	Tool      string                 `json:"tool"`
+	Args      map[string]interface{} `json:"args"`
+	Namespace string                 `json:"namespace"`
+	Resource  string                 `json:"resource"`
+	Verb      string                 `json:"verb"`
+	Caller    policy.Caller          `json:"caller"`
+	Context   string                 `json:"context"`
+	// Expect is the effect the call should get; the test fails otherwise.
+	Expect policy.Effect `json:"expect"`
+}
+
+var policyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "Work with the tool call policy",
+}
+
+var policyTestCmd = &cobra.Command{
+	Use:   "test",
+	Short: "Evaluate sample tool calls against the policy offline",
+	Long: `Evaluate the sample tool calls in a YAML file against the policy from the
+config file, or from --policy, and print the decision for each call.
+
+Example calls file:
+
+  - name: delete in production
+    tool: kubectl_delete
+    args: {resource: pod, name: web, namespace: prod-eu}
+    caller: {user: alice, groups: [dev]}
+    context: prod
+    expect: deny`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		callsFile, _ := cmd.Flags().GetString("calls")
+		policyFile, _ := cmd.Flags().GetString("policy")
+		defaultNamespace, _ := cmd.Flags().GetString("namespace")
+
+		p := cfg.Policy
+		if policyFile != "" {
+			data, err := os.ReadFile(policyFile)
+			if err != nil {
+				return fmt.Errorf("failed to read policy: %w", err)
+			}
+			p = policy.Policy{}
+			if err := yaml.Unmarshal(data, &p); err != nil {
+				return fmt.Errorf("failed to parse policy: %w", err)
+			}
+		}
+		engine, err := policy.New(p)
+		if err != nil {
+			return fmt.Errorf("invalid policy: %w", err)
+		}
+
+		data, err := os.ReadFile(callsFile)
+		if err != nil {
+			return fmt.Errorf("failed to read calls: %w", err)
+		}
+		var calls []policyTestCall
+		if err := yaml.Unmarshal(data, &calls); err != nil {
+			return fmt.Errorf("failed to parse calls: %w", err)
+		}
+
+		failed := 0
+		w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "CALL\tTOOL\tVERB\tNAMESPACE\tEFFECT\tRULE\tRESULT\tMESSAGE")
+		for i, call := range calls {
+			req := tool.PolicyRequest(call.Tool, call.Args, defaultNamespace)
+			if call.Namespace != "" {
+				req.Namespace = call.Namespace
+				req.Unknown = slices.DeleteFunc(req.Unknown, func(field string) bool { return field == "namespace" })
+			}
+			if call.Resource != "" {
+				req.Resource = call.Resource
+				req.Unknown = slices.DeleteFunc(req.Unknown, func(field string) bool { return field == "resource" })
+			}
+			if call.Verb != "" {
+				req.Verb = call.Verb
+			}
+			req.Caller = call.Caller
+			req.Context = call.Context
+
+			decision := engine.Evaluate(req)
+			result := "-"
+			if call.Expect != "" {
+				result = "PASS"
+				if decision.Effect != call.Expect {
+					result = fmt.Sprintf("FAIL (expected %s)", call.Expect)
+					failed++
+				}
+			}
+			name := call.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				name, call.Tool, req.Verb, req.Namespace, decision.Effect, dash(decision.Rule), result, dash(decision.Message))
+		}
+		_ = w.Flush()
+
+		if failed > 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("%d of %d calls did not get the expected effect", failed, len(calls))
+		}
+		return nil
+	},
+}
+
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+func init() {
+	policyTestCmd.Flags().String("calls", "", "YAML file with the sample tool calls")
+	_ = policyTestCmd.MarkFlagRequired("calls")
+	policyTestCmd.Flags().String("policy", "", "YAML file with the policy to test instead of the one in the config file")
+	policyTestCmd.Flags().String("namespace", "default", "namespace assumed for calls that don't specify one")
+
+	policyCmd.AddCommand(policyTestCmd)
+	rootCmd.AddCommand(policyCmd)
+}
@@ -3,6 +3,7 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/idebeijer/kube-mcp-server/internal/config"
 	"github.com/idebeijer/kube-mcp-server/internal/mcpserver"
@@ -73,6 +74,57 @@ func init() {
 
 	rootCmd.PersistentFlags().Bool("disable-kubectl", false, "disable kubectl tools")
 	_ = viper.BindPFlag("disableKubectl", rootCmd.PersistentFlags().Lookup("disable-kubectl"))
+
+	rootCmd.PersistentFlags().String("field-manager", "kube-mcp-server", "field manager name used for server-side apply")
+	_ = viper.BindPFlag("fieldManager", rootCmd.PersistentFlags().Lookup("field-manager"))
+
+	rootCmd.PersistentFlags().String("permission-check", "annotate", "how to treat tools the current identity is not allowed to use (off, annotate or hide)")
+	_ = viper.BindPFlag("permissionCheck", rootCmd.PersistentFlags().Lookup("permission-check"))
+
+	rootCmd.PersistentFlags().Int("max-output-bytes", 64*1024, "size above which tool output is summarised or truncated (0 disables the limit)")
+	_ = viper.BindPFlag("maxOutputBytes", rootCmd.PersistentFlags().Lookup("max-output-bytes"))
+
+	rootCmd.PersistentFlags().Bool("disable-confirmation", false, "run destructive tool calls (deletes, drains, forced applies, kubectl writes) without asking for confirmation")
+	_ = viper.BindPFlag("disableConfirmation", rootCmd.PersistentFlags().Lookup("disable-confirmation"))
+
+	rootCmd.PersistentFlags().Duration("confirm-token-ttl", 5*time.Minute, "how long a confirm_token for clients without elicitation support stays valid")
+	_ = viper.BindPFlag("confirmTokenTTL", rootCmd.PersistentFlags().Lookup("confirm-token-ttl"))
+
+	rootCmd.PersistentFlags().String("profile", "", "tool profile to expose (readonly, operator or admin; default all tools)")
+	_ = viper.BindPFlag("profile", rootCmd.PersistentFlags().Lookup("profile"))
+
+	rootCmd.PersistentFlags().StringSlice("enabled-tools", nil, "glob patterns of tools to enable in addition to the profile")
+	_ = viper.BindPFlag("enabledTools", rootCmd.PersistentFlags().Lookup("enabled-tools"))
+
+	rootCmd.PersistentFlags().StringSlice("disabled-tools", nil, "glob patterns of tools and resource URIs to disable")
+	_ = viper.BindPFlag("disabledTools", rootCmd.PersistentFlags().Lookup("disabled-tools"))
+
+	rootCmd.PersistentFlags().Float64("rate-limit", 0, "tool calls per second allowed across all sessions (0 disables the limit)")
+	_ = viper.BindPFlag("rateLimits.global.rate", rootCmd.PersistentFlags().Lookup("rate-limit"))
+
+	rootCmd.PersistentFlags().Int("rate-limit-burst", 0, "tool calls allowed in a burst across all sessions (defaults to the rate)")
+	_ = viper.BindPFlag("rateLimits.global.burst", rootCmd.PersistentFlags().Lookup("rate-limit-burst"))
+
+	rootCmd.PersistentFlags().Float64("session-rate-limit", 0, "tool calls per second allowed per session (0 disables the limit)")
+	_ = viper.BindPFlag("rateLimits.perSession.rate", rootCmd.PersistentFlags().Lookup("session-rate-limit"))
+
+	rootCmd.PersistentFlags().Int("session-rate-limit-burst", 0, "tool calls allowed in a burst per session (defaults to the rate)")
+	_ = viper.BindPFlag("rateLimits.perSession.burst", rootCmd.PersistentFlags().Lookup("session-rate-limit-burst"))
+
+	rootCmd.PersistentFlags().Int("max-concurrent-kubectl", 8, "maximum number of kubectl processes running at once (0 removes the cap)")
+	_ = viper.BindPFlag("maxConcurrentKubectl", rootCmd.PersistentFlags().Lookup("max-concurrent-kubectl"))
+
+	rootCmd.PersistentFlags().Float32("kube-api-qps", 0, "queries per second to the Kubernetes API server (0 keeps the client default)")
+	_ = viper.BindPFlag("kubeAPIQPS", rootCmd.PersistentFlags().Lookup("kube-api-qps"))
+
+	rootCmd.PersistentFlags().Int("kube-api-burst", 0, "burst of queries to the Kubernetes API server (0 keeps the client default)")
+	_ = viper.BindPFlag("kubeAPIBurst", rootCmd.PersistentFlags().Lookup("kube-api-burst"))
+
+	rootCmd.PersistentFlags().Duration("tool-timeout", 2*time.Minute, "how long a tool call may run unless it asks for a different timeout")
+	_ = viper.BindPFlag("toolTimeout", rootCmd.PersistentFlags().Lookup("tool-timeout"))
+
+	rootCmd.PersistentFlags().Duration("max-tool-timeout", 10*time.Minute, "longest timeout a tool call can ask for")
+	_ = viper.BindPFlag("maxToolTimeout", rootCmd.PersistentFlags().Lookup("max-tool-timeout"))
 }
 
 func initConfig() {
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"time"
 
+	"github.com/idebeijer/kube-mcp-server/pkg/policy"
 	"github.com/spf13/viper"
 )
 
@@ -15,6 +17,49 @@ type Config struct {
 	Mode              string `mapstructure:"mode"`
 	SSEPort           string `mapstructure:"ssePort"`
 	DisableKubectl    bool   `mapstructure:"disableKubectl"`
+	FieldManager      string `mapstructure:"fieldManager"`
+	PermissionCheck   string `mapstructure:"permissionCheck"`
+	MaxOutputBytes    int    `mapstructure:"maxOutputBytes"`
+	// DisableConfirmation runs destructive tool calls without asking for
+	// confirmation first.
+	DisableConfirmation bool          `mapstructure:"disableConfirmation"`
+	ConfirmTokenTTL     time.Duration `mapstructure:"confirmTokenTTL"`
+	// Profile is a named set of tools (readonly, operator or admin).
+	// EnabledTools adds to it and DisabledTools removes from it; both take
+	// glob patterns, and DisabledTools also matches resource URIs.
+	Profile       string   `mapstructure:"profile"`
+	EnabledTools  []string `mapstructure:"enabledTools"`
+	DisabledTools []string `mapstructure:"disabledTools"`
+	// RateLimits limit tool calls globally, per session and per tool. Limits
+	// per tool can only be set in the config file.
+	RateLimits           RateLimits `mapstructure:"rateLimits"`
+	MaxConcurrentKubectl int        `mapstructure:"maxConcurrentKubectl"`
+	// KubeAPIQPS and KubeAPIBurst configure the client-side rate limit of the
+	// Kubernetes client; zero keeps the client-go defaults.
+	KubeAPIQPS   float32 `mapstructure:"kubeAPIQPS"`
+	KubeAPIBurst int     `mapstructure:"kubeAPIBurst"`
+	// ToolTimeout bounds tool calls; ToolTimeouts overrides it per tool and
+	// can only be set in the config file. Calls can ask for a different
+	// timeout of at most MaxToolTimeout.
+	ToolTimeout    time.Duration            `mapstructure:"toolTimeout"`
+	ToolTimeouts   map[string]time.Duration `mapstructure:"toolTimeouts"`
+	MaxToolTimeout time.Duration            `mapstructure:"maxToolTimeout"`
+	// Policy holds the rules tool calls are checked against. It can only be
+	// set in the config file.
+	Policy policy.Policy `mapstructure:"policy"`
+}
+
+// RateLimit allows Rate calls per second with bursts of up to Burst calls.
+type RateLimit struct {
+	Rate  float64 `mapstructure:"rate"`
+	Burst int     `mapstructure:"burst"`
+}
+
+// RateLimits limit tool calls globally, per session and per tool.
+type RateLimits struct {
+	Global     RateLimit            `mapstructure:"global"`
+	PerSession RateLimit            `mapstructure:"perSession"`
+	Tools      map[string]RateLimit `mapstructure:"tools"`
 }
 
 type Mode string
@@ -1,19 +1,29 @@
 package mcpserver
 
 import (
+	"fmt"
+
 	"github.com/idebeijer/kube-mcp-server/internal/config"
+	"github.com/idebeijer/kube-mcp-server/pkg/kube"
+	"github.com/idebeijer/kube-mcp-server/pkg/policy"
 	"github.com/idebeijer/kube-mcp-server/pkg/resource"
 	"github.com/idebeijer/kube-mcp-server/pkg/tool"
+	"github.com/idebeijer/kube-mcp-server/pkg/toolset"
 	"github.com/mark3labs/mcp-go/server"
 	"github.com/rs/zerolog/log"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 )
 
 type Server struct {
-	mcp    *server.MCPServer
-	client *kubernetes.Clientset
+	mcp      *server.MCPServer
+	client   *kubernetes.Clientset
+	dynamic  dynamic.Interface
+	metadata metadata.Interface
+	registry *resource.Registry
 
 	enableTools     bool
 	enableResources bool
@@ -33,6 +43,14 @@ func WithResources() Option {
 	}
 }
 
+// WithRegistry sets the registry of kinds exposed as resources and by the
+// summarize_resources tool, so callers can register their own summarisers.
+func WithRegistry(registry *resource.Registry) Option {
+	return func(s *Server) {
+		s.registry = registry
+	}
+}
+
 func New(cfg *config.Config, opts ...Option) (*Server, error) {
 	var restCfg *rest.Config
 	var err error
@@ -47,25 +65,56 @@ func New(cfg *config.Config, opts ...Option) (*Server, error) {
 			return nil, err
 		}
 	}
+	if cfg.KubeAPIQPS > 0 {
+		restCfg.QPS = cfg.KubeAPIQPS
+	}
+	if cfg.KubeAPIBurst > 0 {
+		restCfg.Burst = cfg.KubeAPIBurst
+	}
 	client, err := kubernetes.NewForConfig(restCfg)
 	if err != nil {
 		return nil, err
 	}
+	dynamicClient, err := dynamic.NewForConfig(restCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	metadataClient, err := metadata.NewForConfig(restCfg)
+	if err != nil {
+		return nil, err
+	}
 
 	s := &Server{
-		client: client,
+		client:   client,
+		dynamic:  dynamicClient,
+		metadata: metadataClient,
 	}
 
 	for _, opt := range opts {
 		opt(s)
 	}
+	if s.registry == nil {
+		s.registry = resource.NewDefaultRegistry()
+	}
+
+	filter, err := toolset.New(cfg.Profile, cfg.EnabledTools, cfg.DisabledTools)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tool selection: %w", err)
+	}
 
+	hooks := &server.Hooks{}
 	mcpServerOpts := []server.ServerOption{
 		server.WithLogging(),
+		server.WithHooks(hooks),
+	}
+	if instructions := filter.Instructions(); instructions != "" {
+		log.Info().Str("profile", cfg.Profile).Msg("Restricting tools")
+		mcpServerOpts = append(mcpServerOpts, server.WithInstructions(instructions))
 	}
 	if s.enableTools {
 		log.Info().Msg("Enabling tools")
-		mcpServerOpts = append(mcpServerOpts, server.WithToolCapabilities(true))
+		mcpServerOpts = append(mcpServerOpts, server.WithToolCapabilities(true), server.WithElicitation())
 	}
 	if s.enableResources {
 		log.Info().Msg("Enabling resources")
@@ -79,15 +128,41 @@ func New(cfg *config.Config, opts ...Option) (*Server, error) {
 	s.mcp = mcpServer
 
 	if s.enableTools {
-		var toolOpts []tool.Option
+		toolOpts := []tool.Option{
+			tool.WithSummaries(s.registry),
+			tool.WithToolset(filter),
+			tool.WithFieldManager(cfg.FieldManager),
+			tool.WithDefaultNamespace(kube.DefaultNamespace(cfg.Kubeconfig)),
+			tool.WithPermissionCheck(tool.PermissionCheckMode(cfg.PermissionCheck)),
+			tool.WithMaxOutputBytes(cfg.MaxOutputBytes),
+			tool.WithDestructiveConfirmation(!cfg.DisableConfirmation),
+			tool.WithConfirmTokenTTL(cfg.ConfirmTokenTTL),
+			tool.WithRateLimits(toolRateLimits(cfg.RateLimits)),
+			tool.WithMaxConcurrentKubectl(cfg.MaxConcurrentKubectl),
+			tool.WithToolTimeout(cfg.ToolTimeout),
+			tool.WithToolTimeoutOverrides(cfg.ToolTimeouts),
+			tool.WithMaxToolTimeout(cfg.MaxToolTimeout),
+		}
 		if !cfg.DisableKubectl {
 			toolOpts = append(toolOpts, tool.WithKubectlTools())
 		}
-		tools, _ := tool.NewHandler(s.client, cfg.Kubeconfig, toolOpts...)
+		if cfg.Policy.Default != "" || len(cfg.Policy.Rules) > 0 {
+			engine, err := policy.New(cfg.Policy)
+			if err != nil {
+				return nil, fmt.Errorf("invalid policy: %w", err)
+			}
+			log.Info().Int("rules", len(cfg.Policy.Rules)).Msg("Enforcing tool policy")
+			toolOpts = append(toolOpts, tool.WithPolicy(engine))
+		}
+		tools, err := tool.NewHandler(s.client, s.dynamic, s.metadata, cfg.Kubeconfig, toolOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create tool handler: %w", err)
+		}
+		tools.AddHooks(hooks)
 		tools.Register(s.mcp)
 	}
 	if s.enableResources {
-		resources := resource.NewHandler(s.client)
+		resources := resource.NewHandler(s.client, s.dynamic, resource.WithRegistry(s.registry), resource.WithToolset(filter))
 		resources.Register(s.mcp)
 	}
 
@@ -104,3 +179,19 @@ func (s *Server) StartStdio() error {
 	log.Info().Msg("Running in stdio mode. Press Ctrl+C to exit.")
 	return server.ServeStdio(s.mcp)
 }
+
+// toolRateLimits converts the configured rate limits to the ones of the tool
+// handler.
+func toolRateLimits(limits config.RateLimits) tool.RateLimits {
+	result := tool.RateLimits{
+		Global:     tool.RateLimit(limits.Global),
+		PerSession: tool.RateLimit(limits.PerSession),
+	}
+	if len(limits.Tools) > 0 {
+		result.Tools = make(map[string]tool.RateLimit, len(limits.Tools))
+		for name, limit := range limits.Tools {
+			result.Tools[name] = tool.RateLimit(limit)
+		}
+	}
+	return result
+}
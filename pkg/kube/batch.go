@@ -0,0 +1,88 @@
+package kube
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// Job statuses as reported by GetJobStatus.
+const (
+	JobStatusComplete  = "Complete"
+	JobStatusFailed    = "Failed"
+	JobStatusRunning   = "Running"
+	JobStatusSuspended = "Suspended"
+	JobStatusPending   = "Pending"
+)
+
+// GetJobStatus returns a one-word status of the job and, for failed jobs, the
+// reason and message of the failure.
+func GetJobStatus(job *batchv1.Job) (status, reason string) {
+	for _, c := range job.Status.Conditions {
+		if c.Status != corev1.ConditionTrue {
+			continue
+		}
+		switch c.Type {
+		case batchv1.JobComplete:
+			return JobStatusComplete, ""
+		case batchv1.JobFailed:
+			if c.Message != "" {
+				return JobStatusFailed, fmt.Sprintf("%s: %s", c.Reason, c.Message)
+			}
+			return JobStatusFailed, c.Reason
+		case batchv1.JobSuspended:
+			return JobStatusSuspended, ""
+		}
+	}
+	if job.Status.Active > 0 {
+		return JobStatusRunning, ""
+	}
+	return JobStatusPending, ""
+}
+
+// GetJobDuration returns how long the job ran, or has been running so far.
+// It returns zero when the job hasn't started.
+func GetJobDuration(job *batchv1.Job, now time.Time) time.Duration {
+	if job.Status.StartTime == nil {
+		return 0
+	}
+	end := now
+	if job.Status.CompletionTime != nil {
+		end = job.Status.CompletionTime.Time
+	}
+	return end.Sub(job.Status.StartTime.Time).Round(time.Second)
+}
+
+// GetJobCompletions formats the succeeded and desired completions of the job
+// like kubectl, e.g. "1/1".
+func GetJobCompletions(job *batchv1.Job) string {
+	desired := int32(1)
+	if job.Spec.Completions != nil {
+		desired = *job.Spec.Completions
+	} else if job.Spec.Parallelism != nil && *job.Spec.Parallelism > 1 {
+		// Work queue jobs without completions finish when any pod succeeds.
+		return fmt.Sprintf("%d/1 of %d", job.Status.Succeeded, *job.Spec.Parallelism)
+	}
+	return fmt.Sprintf("%d/%d", job.Status.Succeeded, desired)
+}
+
+// GetCronJobNextSchedule computes the next time the CronJob is scheduled after
+// now, honouring spec.timeZone. It returns a zero time for suspended CronJobs.
+func GetCronJobNextSchedule(cronJob *batchv1.CronJob, now time.Time) (time.Time, error) {
+	if cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend {
+		return time.Time{}, nil
+	}
+
+	spec := cronJob.Spec.Schedule
+	if cronJob.Spec.TimeZone != nil && *cronJob.Spec.TimeZone != "" {
+		spec = "CRON_TZ=" + *cronJob.Spec.TimeZone + " " + spec
+	}
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid schedule %q: %w", cronJob.Spec.Schedule, err)
+	}
+	return schedule.Next(now), nil
+}
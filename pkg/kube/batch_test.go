@@ -0,0 +1,83 @@
+package kube
+
+import (
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestGetCronJobNextSchedule(t *testing.T) {
+	now := time.Date(2025, 6, 1, 10, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		spec    batchv1.CronJobSpec
+		want    time.Time
+		wantErr bool
+	}{
+		{"every hour", batchv1.CronJobSpec{Schedule: "0 * * * *"}, time.Date(2025, 6, 1, 11, 0, 0, 0, time.UTC), false},
+		{"descriptor", batchv1.CronJobSpec{Schedule: "@daily"}, time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC), false},
+		{
+			"time zone",
+			batchv1.CronJobSpec{Schedule: "0 12 * * *", TimeZone: ptr.To("Europe/Amsterdam")},
+			time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC), false,
+		},
+		{"suspended", batchv1.CronJobSpec{Schedule: "* * * * *", Suspend: ptr.To(true)}, time.Time{}, false},
+		{"invalid", batchv1.CronJobSpec{Schedule: "every minute"}, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetCronJobNextSchedule(&batchv1.CronJob{Spec: tt.spec}, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetCronJobNextSchedule() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("GetCronJobNextSchedule() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetJobStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		status     batchv1.JobStatus
+		wantStatus string
+		wantReason string
+	}{
+		{"complete", batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobComplete, Status: corev1.ConditionTrue}}}, JobStatusComplete, ""},
+		{
+			"failed",
+			batchv1.JobStatus{Conditions: []batchv1.JobCondition{{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded", Message: "Job has reached the specified backoff limit"}}},
+			JobStatusFailed, "BackoffLimitExceeded: Job has reached the specified backoff limit",
+		},
+		{"running", batchv1.JobStatus{Active: 1}, JobStatusRunning, ""},
+		{"pending", batchv1.JobStatus{}, JobStatusPending, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, reason := GetJobStatus(&batchv1.Job{Status: tt.status})
+			if status != tt.wantStatus || reason != tt.wantReason {
+				t.Errorf("GetJobStatus() = %q, %q, want %q, %q", status, reason, tt.wantStatus, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestGetJobDuration(t *testing.T) {
+	start := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	job := &batchv1.Job{Status: batchv1.JobStatus{
+		StartTime:      ptr.To(metav1.NewTime(start)),
+		CompletionTime: ptr.To(metav1.NewTime(start.Add(90 * time.Second))),
+	}}
+	if got := GetJobDuration(job, start.Add(time.Hour)); got != 90*time.Second {
+		t.Errorf("GetJobDuration() = %v, want 1m30s", got)
+	}
+	if got := GetJobDuration(&batchv1.Job{}, start); got != 0 {
+		t.Errorf("GetJobDuration() = %v for a job that hasn't started, want 0", got)
+	}
+}
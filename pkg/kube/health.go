@@ -0,0 +1,364 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	// warningEventWindow is how far back Warning events are reported.
+	warningEventWindow = time.Hour
+	// maxHealthItems caps each list in the summary, and the names listed per
+	// pod reason. Items beyond the cap are only counted in Omitted.
+	maxHealthItems = 20
+)
+
+// ClusterHealth is a summary of the state of the cluster, meant as the first
+// thing to look at during an incident.
+type ClusterHealth struct {
+	ServerVersion     string              `json:"serverVersion"`
+	APIServerReady    bool                `json:"apiServerReady"`
+	FailedReadyz      []string            `json:"failedReadyzChecks,omitempty"`
+	Nodes             NodeHealth          `json:"nodes"`
+	UnhealthyPods     int                 `json:"unhealthyPods"`
+	PodsByReason      map[string][]string `json:"podsByReason,omitempty"`
+	DegradedWorkloads []WorkloadHealth    `json:"degradedWorkloads,omitempty"`
+	PendingPVCs       []string            `json:"pendingPVCs,omitempty"`
+	FailedJobs        []FailedJob         `json:"failedJobs,omitempty"`
+	WarningEvents     []WarningEvent      `json:"recentWarningEvents,omitempty"`
+	Errors            []string            `json:"errors,omitempty"`
+	// Omitted counts the items left out of each capped list, keyed by the
+	// list's field name ("podsByReason.<reason>" for the pod names).
+	Omitted map[string]int `json:"omitted,omitempty"`
+}
+
+type NodeHealth struct {
+	Total    int           `json:"total"`
+	Ready    int           `json:"ready"`
+	Problems []NodeProblem `json:"problems,omitempty"`
+}
+
+type NodeProblem struct {
+	Name       string   `json:"name"`
+	Conditions []string `json:"conditions"`
+}
+
+type WorkloadHealth struct {
+	Kind        string `json:"kind"`
+	Name        string `json:"name"`
+	Desired     int32  `json:"desired"`
+	Unavailable int32  `json:"unavailable"`
+}
+
+type FailedJob struct {
+	Name    string `json:"name"`
+	Reason  string `json:"reason"`
+	Message string `json:"message,omitempty"`
+}
+
+type WarningEvent struct {
+	Object   string `json:"object"`
+	Reason   string `json:"reason"`
+	Message  string `json:"message"`
+	Count    int32  `json:"count"`
+	LastSeen string `json:"lastSeen"`
+}
+
+// GetClusterHealth collects a ClusterHealth summary. Failures of individual
+// checks are recorded in Errors so a partial summary is still returned.
+func GetClusterHealth(ctx context.Context, client kubernetes.Interface) *ClusterHealth {
+	health := &ClusterHealth{ServerVersion: "unknown"}
+	fail := func(check string, err error) {
+		health.Errors = append(health.Errors, fmt.Sprintf("%s: %v", check, err))
+	}
+	omit := func(field string, omitted int) {
+		if omitted == 0 {
+			return
+		}
+		if health.Omitted == nil {
+			health.Omitted = map[string]int{}
+		}
+		health.Omitted[field] = omitted
+	}
+
+	if info, err := client.Discovery().ServerVersion(); err == nil {
+		health.ServerVersion = info.GitVersion
+	} else {
+		fail("server version", err)
+	}
+
+	if rest := client.Discovery().RESTClient(); rest != nil {
+		// /readyz returns the verbose check list with a non-2xx status when not ready.
+		body, err := rest.Get().AbsPath("/readyz").Param("verbose", "").DoRaw(ctx)
+		health.APIServerReady = err == nil
+		health.FailedReadyz = failedReadyzChecks(string(body))
+		if err != nil && len(health.FailedReadyz) == 0 {
+			fail("readyz", err)
+		}
+	}
+
+	if nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{}); err == nil {
+		health.Nodes = nodeHealth(nodes.Items)
+	} else {
+		fail("nodes", err)
+	}
+
+	if pods, err := client.CoreV1().Pods("").List(ctx, metav1.ListOptions{}); err == nil {
+		health.PodsByReason = unhealthyPods(pods.Items)
+		for reason, names := range health.PodsByReason {
+			health.UnhealthyPods += len(names)
+			var omitted int
+			health.PodsByReason[reason], omitted = capList(names)
+			omit("podsByReason."+reason, omitted)
+		}
+	} else {
+		fail("pods", err)
+	}
+
+	if deployments, err := client.AppsV1().Deployments("").List(ctx, metav1.ListOptions{}); err == nil {
+		health.DegradedWorkloads = append(health.DegradedWorkloads, degradedDeployments(deployments.Items)...)
+	} else {
+		fail("deployments", err)
+	}
+	if statefulSets, err := client.AppsV1().StatefulSets("").List(ctx, metav1.ListOptions{}); err == nil {
+		health.DegradedWorkloads = append(health.DegradedWorkloads, degradedStatefulSets(statefulSets.Items)...)
+	} else {
+		fail("statefulsets", err)
+	}
+	if daemonSets, err := client.AppsV1().DaemonSets("").List(ctx, metav1.ListOptions{}); err == nil {
+		health.DegradedWorkloads = append(health.DegradedWorkloads, degradedDaemonSets(daemonSets.Items)...)
+	} else {
+		fail("daemonsets", err)
+	}
+
+	var omitted int
+	health.DegradedWorkloads, omitted = capList(health.DegradedWorkloads)
+	omit("degradedWorkloads", omitted)
+
+	if pvcs, err := client.CoreV1().PersistentVolumeClaims("").List(ctx, metav1.ListOptions{}); err == nil {
+		for _, pvc := range pvcs.Items {
+			if pvc.Status.Phase == corev1.ClaimPending {
+				health.PendingPVCs = append(health.PendingPVCs, pvc.Namespace+"/"+pvc.Name)
+			}
+		}
+		health.PendingPVCs, omitted = capList(health.PendingPVCs)
+		omit("pendingPVCs", omitted)
+	} else {
+		fail("persistentvolumeclaims", err)
+	}
+
+	if jobs, err := client.BatchV1().Jobs("").List(ctx, metav1.ListOptions{}); err == nil {
+		health.FailedJobs, omitted = capList(failedJobs(jobs.Items))
+		omit("failedJobs", omitted)
+	} else {
+		fail("jobs", err)
+	}
+
+	events, err := client.CoreV1().Events("").List(ctx, metav1.ListOptions{FieldSelector: "type=" + corev1.EventTypeWarning})
+	if err == nil {
+		health.WarningEvents, omitted = capList(recentWarningEvents(events.Items, time.Now()))
+		omit("recentWarningEvents", omitted)
+	} else {
+		fail("events", err)
+	}
+
+	return health
+}
+
+// failedReadyzChecks returns the names of the checks marked as failed ("[-]")
+// in verbose /readyz output.
+func failedReadyzChecks(body string) []string {
+	var failed []string
+	for _, line := range strings.Split(body, "\n") {
+		if name, ok := strings.CutPrefix(strings.TrimSpace(line), "[-]"); ok {
+			failed = append(failed, name)
+		}
+	}
+	return failed
+}
+
+func nodeHealth(nodes []corev1.Node) NodeHealth {
+	health := NodeHealth{Total: len(nodes)}
+	for _, node := range nodes {
+		var problems []string
+		for _, c := range node.Status.Conditions {
+			switch {
+			case c.Type == corev1.NodeReady && c.Status == corev1.ConditionTrue:
+				health.Ready++
+			case c.Type == corev1.NodeReady:
+				problems = append(problems, "NotReady")
+			case c.Status == corev1.ConditionTrue:
+				// All other standard conditions (MemoryPressure, DiskPressure,
+				// PIDPressure, NetworkUnavailable) are problems when true.
+				problems = append(problems, string(c.Type))
+			}
+		}
+		if node.Spec.Unschedulable {
+			problems = append(problems, "SchedulingDisabled")
+		}
+		if len(problems) > 0 {
+			health.Problems = append(health.Problems, NodeProblem{Name: node.Name, Conditions: problems})
+		}
+	}
+	return health
+}
+
+// unhealthyPods groups pods that are not Running or Succeeded by reason, in
+// the order they are listed.
+func unhealthyPods(pods []corev1.Pod) map[string][]string {
+	byReason := map[string][]string{}
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodRunning || pod.Status.Phase == corev1.PodSucceeded {
+			continue
+		}
+		reason := podReason(&pod)
+		byReason[reason] = append(byReason[reason], pod.Namespace+"/"+pod.Name)
+	}
+	return byReason
+}
+
+// podReason returns the most specific reason a pod is not running, preferring
+// container waiting/terminated reasons (e.g. ImagePullBackOff) over the phase.
+func podReason(pod *corev1.Pod) string {
+	statuses := append(append([]corev1.ContainerStatus{}, pod.Status.InitContainerStatuses...), pod.Status.ContainerStatuses...)
+	for _, s := range statuses {
+		if s.State.Waiting != nil && s.State.Waiting.Reason != "" && s.State.Waiting.Reason != "PodInitializing" {
+			return s.State.Waiting.Reason
+		}
+		if s.State.Terminated != nil && s.State.Terminated.ExitCode != 0 && s.State.Terminated.Reason != "" {
+			return s.State.Terminated.Reason
+		}
+	}
+	if pod.Status.Reason != "" {
+		return pod.Status.Reason
+	}
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodScheduled && c.Status == corev1.ConditionFalse && c.Reason != "" {
+			return c.Reason
+		}
+	}
+	if pod.Status.Phase == "" {
+		return string(corev1.PodUnknown)
+	}
+	return string(pod.Status.Phase)
+}
+
+func degradedDeployments(deployments []appsv1.Deployment) []WorkloadHealth {
+	var result []WorkloadHealth
+	for _, d := range deployments {
+		desired := int32(1)
+		if d.Spec.Replicas != nil {
+			desired = *d.Spec.Replicas
+		}
+		if unavailable := desired - d.Status.AvailableReplicas; unavailable > 0 {
+			result = append(result, WorkloadHealth{Kind: "Deployment", Name: d.Namespace + "/" + d.Name, Desired: desired, Unavailable: unavailable})
+		}
+	}
+	return result
+}
+
+func degradedStatefulSets(statefulSets []appsv1.StatefulSet) []WorkloadHealth {
+	var result []WorkloadHealth
+	for _, s := range statefulSets {
+		desired := int32(1)
+		if s.Spec.Replicas != nil {
+			desired = *s.Spec.Replicas
+		}
+		if unavailable := desired - s.Status.AvailableReplicas; unavailable > 0 {
+			result = append(result, WorkloadHealth{Kind: "StatefulSet", Name: s.Namespace + "/" + s.Name, Desired: desired, Unavailable: unavailable})
+		}
+	}
+	return result
+}
+
+func degradedDaemonSets(daemonSets []appsv1.DaemonSet) []WorkloadHealth {
+	var result []WorkloadHealth
+	for _, d := range daemonSets {
+		if d.Status.NumberUnavailable > 0 {
+			result = append(result, WorkloadHealth{Kind: "DaemonSet", Name: d.Namespace + "/" + d.Name, Desired: d.Status.DesiredNumberScheduled, Unavailable: d.Status.NumberUnavailable})
+		}
+	}
+	return result
+}
+
+func failedJobs(jobs []batchv1.Job) []FailedJob {
+	var result []FailedJob
+	for _, job := range jobs {
+		for _, c := range job.Status.Conditions {
+			if c.Type == batchv1.JobFailed && c.Status == corev1.ConditionTrue {
+				result = append(result, FailedJob{Name: job.Namespace + "/" + job.Name, Reason: c.Reason, Message: c.Message})
+				break
+			}
+		}
+	}
+	return result
+}
+
+// recentWarningEvents returns the Warning events seen within the event
+// window, newest first.
+func recentWarningEvents(events []corev1.Event, now time.Time) []WarningEvent {
+	type timedEvent struct {
+		seen  time.Time
+		event WarningEvent
+	}
+
+	var recent []timedEvent
+	for _, e := range events {
+		if e.Type != corev1.EventTypeWarning {
+			continue
+		}
+		seen := eventTime(&e)
+		if now.Sub(seen) > warningEventWindow {
+			continue
+		}
+		count := e.Count
+		if e.Series != nil {
+			count = e.Series.Count
+		}
+		recent = append(recent, timedEvent{seen: seen, event: WarningEvent{
+			Object:   fmt.Sprintf("%s %s/%s", e.InvolvedObject.Kind, e.InvolvedObject.Namespace, e.InvolvedObject.Name),
+			Reason:   e.Reason,
+			Message:  strings.TrimSpace(e.Message),
+			Count:    count,
+			LastSeen: now.Sub(seen).Round(time.Second).String() + " ago",
+		}})
+	}
+
+	sort.SliceStable(recent, func(i, j int) bool { return recent[i].seen.After(recent[j].seen) })
+
+	result := make([]WarningEvent, 0, len(recent))
+	for _, e := range recent {
+		result = append(result, e.event)
+	}
+	return result
+}
+
+// capList returns the first maxHealthItems items and the number left out.
+func capList[T any](items []T) ([]T, int) {
+	if len(items) <= maxHealthItems {
+		return items, 0
+	}
+	return items[:maxHealthItems], len(items) - maxHealthItems
+}
+
+func eventTime(e *corev1.Event) time.Time {
+	switch {
+	case e.Series != nil && !e.Series.LastObservedTime.IsZero():
+		return e.Series.LastObservedTime.Time
+	case !e.LastTimestamp.IsZero():
+		return e.LastTimestamp.Time
+	case !e.EventTime.IsZero():
+		return e.EventTime.Time
+	default:
+		return e.CreationTimestamp.Time
+	}
+}
@@ -0,0 +1,184 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestFailedReadyzChecks(t *testing.T) {
+	body := "[+]ping ok\n[+]log ok\n[-]etcd failed: reason withheld\n[+]poststarthook/start-informers ok\nreadyz check failed\n"
+	want := []string{"etcd failed: reason withheld"}
+	if got := failedReadyzChecks(body); !reflect.DeepEqual(got, want) {
+		t.Errorf("failedReadyzChecks() = %v, want %v", got, want)
+	}
+}
+
+func TestNodeHealth(t *testing.T) {
+	nodes := []corev1.Node{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "healthy"},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionTrue},
+				{Type: corev1.NodeMemoryPressure, Status: corev1.ConditionFalse},
+			}},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "broken"},
+			Spec:       corev1.NodeSpec{Unschedulable: true},
+			Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+				{Type: corev1.NodeReady, Status: corev1.ConditionUnknown},
+				{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue},
+			}},
+		},
+	}
+
+	want := NodeHealth{Total: 2, Ready: 1, Problems: []NodeProblem{
+		{Name: "broken", Conditions: []string{"NotReady", "DiskPressure", "SchedulingDisabled"}},
+	}}
+	if got := nodeHealth(nodes); !reflect.DeepEqual(got, want) {
+		t.Errorf("nodeHealth() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPodReason(t *testing.T) {
+	tests := []struct {
+		name string
+		pod  corev1.Pod
+		want string
+	}{
+		{
+			name: "image pull back-off",
+			pod: corev1.Pod{Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				ContainerStatuses: []corev1.ContainerStatus{{
+					State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}},
+				}},
+			}},
+			want: "ImagePullBackOff",
+		},
+		{
+			name: "evicted",
+			pod:  corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted"}},
+			want: "Evicted",
+		},
+		{
+			name: "unschedulable",
+			pod: corev1.Pod{Status: corev1.PodStatus{
+				Phase:      corev1.PodPending,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodScheduled, Status: corev1.ConditionFalse, Reason: "Unschedulable"}},
+			}},
+			want: "Unschedulable",
+		},
+		{
+			name: "phase fallback",
+			pod:  corev1.Pod{Status: corev1.PodStatus{Phase: corev1.PodFailed}},
+			want: "Failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := podReason(&tt.pod); got != tt.want {
+				t.Errorf("podReason() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecentWarningEvents(t *testing.T) {
+	now := time.Now()
+	event := func(reason string, age time.Duration) corev1.Event {
+		return corev1.Event{
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			LastTimestamp:  metav1.NewTime(now.Add(-age)),
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "app"},
+		}
+	}
+
+	events := []corev1.Event{
+		event("Old", 2*time.Hour),
+		event("BackOff", 5*time.Minute),
+		event("FailedScheduling", time.Minute),
+		{Type: corev1.EventTypeNormal, Reason: "Pulled", LastTimestamp: metav1.NewTime(now)},
+	}
+
+	got := recentWarningEvents(events, now)
+	if len(got) != 2 || got[0].Reason != "FailedScheduling" || got[1].Reason != "BackOff" {
+		t.Errorf("recentWarningEvents() = %+v, want FailedScheduling then BackOff", got)
+	}
+}
+
+func TestGetClusterHealthCapsLists(t *testing.T) {
+	now := time.Now()
+	var objects []runtime.Object
+	for i := 0; i < maxHealthItems+5; i++ {
+		objects = append(objects, &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("pending-%02d", i), Namespace: "default"},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		})
+	}
+	objects = append(objects, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "failed", Namespace: "default"},
+		Status:     corev1.PodStatus{Phase: corev1.PodFailed, Reason: "Evicted"},
+	})
+	for i := 0; i < maxHealthItems+3; i++ {
+		objects = append(objects, &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("data-%02d", i), Namespace: "default"},
+			Status:     corev1.PersistentVolumeClaimStatus{Phase: corev1.ClaimPending},
+		})
+	}
+	for i := 0; i < maxHealthItems+1; i++ {
+		objects = append(objects, &batchv1.Job{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("job-%02d", i), Namespace: "default"},
+			Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+				{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"},
+			}},
+		})
+	}
+	for i := 0; i < maxHealthItems+10; i++ {
+		objects = append(objects, &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: fmt.Sprintf("event-%02d", i), Namespace: "default"},
+			Type:           corev1.EventTypeWarning,
+			Reason:         "BackOff",
+			LastTimestamp:  metav1.NewTime(now.Add(-time.Duration(i) * time.Second)),
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "default", Name: "app"},
+		})
+	}
+
+	health := GetClusterHealth(context.Background(), fake.NewClientset(objects...))
+
+	if health.UnhealthyPods != maxHealthItems+6 {
+		t.Errorf("UnhealthyPods = %d, want %d", health.UnhealthyPods, maxHealthItems+6)
+	}
+	if got := len(health.PodsByReason["Pending"]); got != maxHealthItems {
+		t.Errorf("%d Pending pods listed, want %d", got, maxHealthItems)
+	}
+	if got := health.PodsByReason["Evicted"]; !reflect.DeepEqual(got, []string{"default/failed"}) {
+		t.Errorf("Evicted pods = %v, want default/failed", got)
+	}
+	if len(health.PendingPVCs) != maxHealthItems || len(health.FailedJobs) != maxHealthItems || len(health.WarningEvents) != maxHealthItems {
+		t.Errorf("listed %d PVCs, %d jobs and %d events, want %d of each",
+			len(health.PendingPVCs), len(health.FailedJobs), len(health.WarningEvents), maxHealthItems)
+	}
+	if health.WarningEvents[0].LastSeen != "0s ago" {
+		t.Errorf("first warning event seen %s, want the newest", health.WarningEvents[0].LastSeen)
+	}
+	wantOmitted := map[string]int{
+		"podsByReason.Pending": 5,
+		"pendingPVCs":          3,
+		"failedJobs":           1,
+		"recentWarningEvents":  10,
+	}
+	if !reflect.DeepEqual(health.Omitted, wantOmitted) {
+		t.Errorf("Omitted = %v, want %v", health.Omitted, wantOmitted)
+	}
+}
@@ -0,0 +1,46 @@
+package kube
+
+import (
+	"os"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// DefaultNamespace returns the namespace of the current context in the given
+// kubeconfig, or the service account namespace when running in-cluster. It
+// falls back to "default".
+func DefaultNamespace(kubeconfigPath string) string {
+	if kubeconfigPath != "" {
+		loader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfigPath},
+			&clientcmd.ConfigOverrides{},
+		)
+		if ns, _, err := loader.Namespace(); err == nil && ns != "" {
+			return ns
+		}
+		return "default"
+	}
+
+	if data, err := os.ReadFile(serviceAccountNamespaceFile); err == nil {
+		if ns := strings.TrimSpace(string(data)); ns != "" {
+			return ns
+		}
+	}
+	return "default"
+}
+
+// CurrentContext returns the name of the current context in the given
+// kubeconfig, or "in-cluster" when running without one.
+func CurrentContext(kubeconfigPath string) string {
+	if kubeconfigPath == "" {
+		return "in-cluster"
+	}
+	config, err := clientcmd.LoadFromFile(kubeconfigPath)
+	if err != nil {
+		return ""
+	}
+	return config.CurrentContext
+}
@@ -0,0 +1,176 @@
+// Package policy decides whether tool calls are allowed by evaluating CEL
+// rules against the call.
+package policy
+
+import (
+	"fmt"
+
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/ext"
+)
+
+// Effect is the outcome of a rule.
+type Effect string
+
+const (
+	EffectAllow   Effect = "allow"
+	EffectDeny    Effect = "deny"
+	EffectConfirm Effect = "confirm"
+)
+
+// Rule applies its effect to calls for which When evaluates to true. When is a
+// CEL expression over the variable request, which holds the fields of Request,
+// e.g. `request.verb == "delete" && request.namespace.startsWith("prod-")`.
+// The request is a single variable because namespace is reserved in CEL.
+type Rule struct {
+	Name    string `mapstructure:"name" json:"name"`
+	When    string `mapstructure:"when" json:"when"`
+	Effect  Effect `mapstructure:"effect" json:"effect"`
+	Message string `mapstructure:"message" json:"message,omitempty"`
+}
+
+// Policy is an ordered list of rules. The first matching rule decides; calls
+// no rule matches get the default effect, which is allow when empty.
+type Policy struct {
+	Default Effect `mapstructure:"default" json:"default,omitempty"`
+	Rules   []Rule `mapstructure:"rules" json:"rules,omitempty"`
+}
+
+// Caller identifies who makes the call.
+type Caller struct {
+	User   string   `json:"user"`
+	Groups []string `json:"groups"`
+}
+
+// Request is a tool call as seen by the rules.
+type Request struct {
+	Tool      string                 `json:"tool"`
+	Args      map[string]interface{} `json:"args"`
+	Namespace string                 `json:"namespace"`
+	Resource  string                 `json:"resource"`
+	Verb      string                 `json:"verb"`
+	Caller    Caller                 `json:"caller"`
+	Context   string                 `json:"context"`
+	// Unknown names the fields, namespace or resource, that can't be told
+	// from the call. They are left out of the request, so rules reading them
+	// fail to evaluate and deny the call.
+	Unknown []string `json:"unknown,omitempty"`
+}
+
+// Decision is the result of evaluating a policy.
+type Decision struct {
+	Effect  Effect `json:"effect"`
+	Rule    string `json:"rule,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type compiledRule struct {
+	Rule
+	program cel.Program
+}
+
+// Engine evaluates a compiled policy.
+type Engine struct {
+	defaultEffect Effect
+	rules         []compiledRule
+}
+
+// New compiles the rules of the policy.
+func New(p Policy) (*Engine, error) {
+	env, err := cel.NewEnv(
+		ext.Strings(),
+		cel.CrossTypeNumericComparisons(true),
+		cel.Variable("request", cel.MapType(cel.StringType, cel.DynType)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CEL environment: %w", err)
+	}
+
+	e := &Engine{defaultEffect: EffectAllow}
+	if p.Default != "" {
+		if !validEffect(p.Default) {
+			return nil, fmt.Errorf("invalid default effect %q (expected allow, deny or confirm)", p.Default)
+		}
+		e.defaultEffect = p.Default
+	}
+
+	for i, rule := range p.Rules {
+		if rule.Name == "" {
+			rule.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		if !validEffect(rule.Effect) {
+			return nil, fmt.Errorf("%s: invalid effect %q (expected allow, deny or confirm)", rule.Name, rule.Effect)
+		}
+		ast, issues := env.Compile(rule.When)
+		if issues.Err() != nil {
+			return nil, fmt.Errorf("%s: %w", rule.Name, issues.Err())
+		}
+		if t := ast.OutputType(); t != cel.BoolType && t != cel.DynType {
+			return nil, fmt.Errorf("%s: expression must evaluate to a bool, got %s", rule.Name, ast.OutputType())
+		}
+		program, err := env.Program(ast)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", rule.Name, err)
+		}
+		e.rules = append(e.rules, compiledRule{Rule: rule, program: program})
+	}
+	return e, nil
+}
+
+// Evaluate returns the decision of the first rule matching the request. A
+// rule that fails to evaluate to a bool, e.g. because it reads a missing
+// argument, denies the call so mistakes in rules fail closed.
+func (e *Engine) Evaluate(req Request) Decision {
+	groups := req.Caller.Groups
+	if groups == nil {
+		groups = []string{}
+	}
+	args := req.Args
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	request := map[string]interface{}{
+		"tool":      req.Tool,
+		"args":      args,
+		"namespace": req.Namespace,
+		"resource":  req.Resource,
+		"verb":      req.Verb,
+		"caller":    map[string]interface{}{"user": req.Caller.User, "groups": groups},
+		"context":   req.Context,
+	}
+	for _, field := range req.Unknown {
+		delete(request, field)
+	}
+	vars := map[string]interface{}{"request": request}
+
+	for _, rule := range e.rules {
+		out, _, err := rule.program.Eval(vars)
+		if err != nil {
+			return Decision{
+				Effect:  EffectDeny,
+				Rule:    rule.Name,
+				Message: fmt.Sprintf("policy rule failed to evaluate: %v", err),
+			}
+		}
+		matched, ok := out.Value().(bool)
+		if !ok {
+			return Decision{
+				Effect:  EffectDeny,
+				Rule:    rule.Name,
+				Message: fmt.Sprintf("policy rule evaluated to %v instead of a bool", out.Value()),
+			}
+		}
+		if matched {
+			return Decision{Effect: rule.Effect, Rule: rule.Name, Message: rule.Message}
+		}
+	}
+	return Decision{Effect: e.defaultEffect}
+}
+
+func validEffect(effect Effect) bool {
+	switch effect {
+	case EffectAllow, EffectDeny, EffectConfirm:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,182 @@
+package policy
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEngineEvaluate(t *testing.T) {
+	engine, err := New(Policy{Rules: []Rule{
+		{
+			Name:    "no-prod-delete",
+			When:    `request.verb == "delete" && request.namespace.matches("^prod-")`,
+			Effect:  EffectDeny,
+			Message: "deleting in production namespaces is not allowed",
+		},
+		{
+			Name:   "scale-limit",
+			When:   `request.verb == "scale" && int(request.args.flags.replicas) > 10`,
+			Effect: EffectDeny,
+		},
+		{
+			Name:   "exec-sre-only",
+			When:   `request.verb == "exec" && !("sre" in request.caller.groups)`,
+			Effect: EffectDeny,
+		},
+		{
+			Name:   "confirm-apply-prod",
+			When:   `request.tool == "apply_manifest" && request.context == "prod"`,
+			Effect: EffectConfirm,
+		},
+		{
+			Name:   "non-bool",
+			When:   `request.tool == "scale_check" && request.args.enabled`,
+			Effect: EffectAllow,
+		},
+		{
+			Name:   "typo",
+			When:   `request.tool == "patch_resource" && request.args.replica > 1`,
+			Effect: EffectAllow,
+		},
+	}})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		req      Request
+		want     Effect
+		wantRule string
+	}{
+		{
+			name:     "delete in prod",
+			req:      Request{Tool: "kubectl_delete", Verb: "delete", Namespace: "prod-eu"},
+			want:     EffectDeny,
+			wantRule: "no-prod-delete",
+		},
+		{
+			name: "delete in dev",
+			req:  Request{Tool: "kubectl_delete", Verb: "delete", Namespace: "dev"},
+			want: EffectAllow,
+		},
+		{
+			name: "scale within limit",
+			req: Request{Tool: "kubectl_generic", Verb: "scale",
+				Args: map[string]interface{}{"flags": map[string]interface{}{"replicas": "3"}}},
+			want: EffectAllow,
+		},
+		{
+			name: "scale over limit",
+			req: Request{Tool: "kubectl_generic", Verb: "scale",
+				Args: map[string]interface{}{"flags": map[string]interface{}{"replicas": "20"}}},
+			want:     EffectDeny,
+			wantRule: "scale-limit",
+		},
+		{
+			name:     "exec outside sre",
+			req:      Request{Tool: "kubectl_generic", Verb: "exec", Caller: Caller{User: "alice", Groups: []string{"dev"}}},
+			want:     EffectDeny,
+			wantRule: "exec-sre-only",
+		},
+		{
+			name: "exec as sre",
+			req:  Request{Tool: "kubectl_generic", Verb: "exec", Caller: Caller{User: "bob", Groups: []string{"sre"}}},
+			want: EffectAllow,
+		},
+		{
+			name:     "confirmation",
+			req:      Request{Tool: "apply_manifest", Verb: "patch", Context: "prod"},
+			want:     EffectConfirm,
+			wantRule: "confirm-apply-prod",
+		},
+		{
+			name:     "non-bool result fails closed",
+			req:      Request{Tool: "scale_check", Args: map[string]interface{}{"enabled": "yes"}},
+			want:     EffectDeny,
+			wantRule: "non-bool",
+		},
+		{
+			name:     "unknown namespace fails closed",
+			req:      Request{Tool: "kubectl_generic", Verb: "delete", Unknown: []string{"namespace"}},
+			want:     EffectDeny,
+			wantRule: "no-prod-delete",
+		},
+		{
+			name: "unknown namespace not read",
+			req:  Request{Tool: "kubectl_generic", Verb: "get", Unknown: []string{"namespace"}},
+			want: EffectAllow,
+		},
+		{
+			name:     "evaluation error fails closed",
+			req:      Request{Tool: "patch_resource", Verb: "patch"},
+			want:     EffectDeny,
+			wantRule: "typo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := engine.Evaluate(tt.req)
+			if got.Effect != tt.want || got.Rule != tt.wantRule {
+				t.Errorf("Evaluate() = %+v, want effect %s from rule %q", got, tt.want, tt.wantRule)
+			}
+		})
+	}
+}
+
+func TestNewRejectsInvalidPolicies(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		wantErr string
+	}{
+		{
+			name:    "syntax error",
+			policy:  Policy{Rules: []Rule{{Name: "broken", When: `request.verb ==`, Effect: EffectDeny}}},
+			wantErr: "broken",
+		},
+		{
+			name:    "not a bool",
+			policy:  Policy{Rules: []Rule{{When: `"prod"`, Effect: EffectDeny}}},
+			wantErr: "must evaluate to a bool",
+		},
+		{
+			name:    "unknown variable",
+			policy:  Policy{Rules: []Rule{{When: `user == "alice"`, Effect: EffectDeny}}},
+			wantErr: "undeclared reference",
+		},
+		{
+			name:    "invalid effect",
+			policy:  Policy{Rules: []Rule{{When: `true`, Effect: "block"}}},
+			wantErr: "invalid effect",
+		},
+		{
+			name:    "invalid default",
+			policy:  Policy{Default: "maybe"},
+			wantErr: "invalid default effect",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(tt.policy)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("New() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEngineDefaultEffect(t *testing.T) {
+	engine, err := New(Policy{Default: EffectDeny, Rules: []Rule{
+		{When: `request.verb in ["get", "list"]`, Effect: EffectAllow},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := engine.Evaluate(Request{Verb: "list"}); got.Effect != EffectAllow {
+		t.Errorf("Evaluate(list) = %s, want allow", got.Effect)
+	}
+	if got := engine.Evaluate(Request{Verb: "delete"}); got.Effect != EffectDeny || got.Rule != "" {
+		t.Errorf("Evaluate(delete) = %+v, want the default deny", got)
+	}
+}
@@ -0,0 +1,33 @@
+package resource
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+var configMapsKind = Kind{
+	Name:       "configmaps",
+	Title:      "ConfigMaps",
+	GVR:        corev1.SchemeGroupVersion.WithResource("configmaps"),
+	Namespaced: true,
+	Summarise:  Typed(summariseConfigMap),
+	Columns: []Column{
+		{Header: "DATA", Field: "totalKeys"},
+	},
+}
+
+// summariseConfigMap reports the keys and value sizes of a ConfigMap, but
+// never the values themselves.
+func summariseConfigMap(c *corev1.ConfigMap) map[string]interface{} {
+	keys := map[string]int{}
+	for k, v := range c.Data {
+		keys[k] = len(v)
+	}
+	for k, v := range c.BinaryData {
+		keys[k] = len(v)
+	}
+	return map[string]interface{}{
+		"keys":      keys,
+		"totalKeys": len(keys),
+		"immutable": c.Immutable != nil && *c.Immutable,
+	}
+}
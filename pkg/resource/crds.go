@@ -0,0 +1,65 @@
+package resource
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// crdsKind summarises CustomResourceDefinitions from unstructured objects, so
+// the apiextensions client isn't needed.
+var crdsKind = Kind{
+	Name:      "crds",
+	Title:     "CustomResourceDefinitions",
+	GVR:       schema.GroupVersionResource{Group: "apiextensions.k8s.io", Version: "v1", Resource: "customresourcedefinitions"},
+	Summarise: summariseCRD,
+	Columns: []Column{
+		{Header: "GROUP", Field: "group"},
+		{Header: "KIND", Field: "kind"},
+		{Header: "SCOPE", Field: "scope"},
+		{Header: "ESTABLISHED", Field: "established"},
+	},
+}
+
+func summariseCRD(crd *unstructured.Unstructured) (map[string]interface{}, error) {
+	group, _, _ := unstructured.NestedString(crd.Object, "spec", "group")
+	kind, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "kind")
+	plural, _, _ := unstructured.NestedString(crd.Object, "spec", "names", "plural")
+	scope, _, _ := unstructured.NestedString(crd.Object, "spec", "scope")
+
+	var versions []map[string]interface{}
+	specVersions, _, _ := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	for _, v := range specVersions {
+		version, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(version, "name")
+		served, _, _ := unstructured.NestedBool(version, "served")
+		storage, _, _ := unstructured.NestedBool(version, "storage")
+		deprecated, _, _ := unstructured.NestedBool(version, "deprecated")
+		versions = append(versions, map[string]interface{}{
+			"name":       name,
+			"served":     served,
+			"storage":    storage,
+			"deprecated": deprecated,
+		})
+	}
+
+	established := false
+	conditions, _, _ := unstructured.NestedSlice(crd.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if ok && condition["type"] == "Established" {
+			established = condition["status"] == "True"
+		}
+	}
+
+	return map[string]interface{}{
+		"group":       group,
+		"kind":        kind,
+		"plural":      plural,
+		"scope":       scope,
+		"versions":    versions,
+		"established": established,
+	}, nil
+}
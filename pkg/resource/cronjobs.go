@@ -0,0 +1,48 @@
+package resource
+
+import (
+	"time"
+
+	"github.com/idebeijer/kube-mcp-server/pkg/kube"
+	batchv1 "k8s.io/api/batch/v1"
+)
+
+var cronJobsKind = Kind{
+	Name:       "cronjobs",
+	Title:      "CronJobs",
+	GVR:        batchv1.SchemeGroupVersion.WithResource("cronjobs"),
+	Namespaced: true,
+	Summarise:  Typed(summariseCronJob),
+	Columns: []Column{
+		{Header: "SCHEDULE", Field: "schedule"},
+		{Header: "SUSPEND", Field: "suspended"},
+		{Header: "ACTIVE", Field: "active"},
+		{Header: "LAST SCHEDULE", Field: "lastSchedule"},
+		{Header: "NEXT SCHEDULE", Field: "nextSchedule"},
+	},
+}
+
+func summariseCronJob(c *batchv1.CronJob) map[string]interface{} {
+	summary := map[string]interface{}{
+		"schedule":  c.Spec.Schedule,
+		"suspended": c.Spec.Suspend != nil && *c.Spec.Suspend,
+		"active":    len(c.Status.Active),
+	}
+	if c.Spec.TimeZone != nil {
+		summary["timeZone"] = *c.Spec.TimeZone
+	}
+	if c.Status.LastScheduleTime != nil {
+		summary["lastSchedule"] = c.Status.LastScheduleTime.UTC().Format(time.RFC3339)
+	}
+	if c.Status.LastSuccessfulTime != nil {
+		summary["lastSuccessful"] = c.Status.LastSuccessfulTime.UTC().Format(time.RFC3339)
+	}
+	next, err := kube.GetCronJobNextSchedule(c, time.Now())
+	switch {
+	case err != nil:
+		summary["nextScheduleError"] = err.Error()
+	case !next.IsZero():
+		summary["nextSchedule"] = next.UTC().Format(time.RFC3339)
+	}
+	return summary
+}
@@ -0,0 +1,32 @@
+package resource
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+var daemonSetsKind = Kind{
+	Name:       "daemonsets",
+	Title:      "DaemonSets",
+	GVR:        appsv1.SchemeGroupVersion.WithResource("daemonsets"),
+	Namespaced: true,
+	Summarise:  Typed(summariseDaemonSet),
+	Columns: []Column{
+		{Header: "DESIRED", Field: "desired"},
+		{Header: "CURRENT", Field: "current"},
+		{Header: "READY", Field: "ready"},
+		{Header: "UP-TO-DATE", Field: "updated"},
+		{Header: "MISSCHEDULED", Field: "misscheduled"},
+	},
+}
+
+func summariseDaemonSet(d *appsv1.DaemonSet) map[string]interface{} {
+	return map[string]interface{}{
+		"desired":      d.Status.DesiredNumberScheduled,
+		"current":      d.Status.CurrentNumberScheduled,
+		"ready":        d.Status.NumberReady,
+		"updated":      d.Status.UpdatedNumberScheduled,
+		"available":    d.Status.NumberAvailable,
+		"misscheduled": d.Status.NumberMisscheduled,
+		"nodeSelector": d.Spec.Template.Spec.NodeSelector,
+	}
+}
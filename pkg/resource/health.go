@@ -0,0 +1,33 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/idebeijer/kube-mcp-server/pkg/kube"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func (h *Handler) registerHealth(m *server.MCPServer) {
+	h.addResource(m, mcp.NewResource("k8s://cluster/health", "Cluster health",
+		mcp.WithResourceDescription("Summary of API server readiness, node conditions, unhealthy pods and workloads, pending PVCs, failed Jobs and recent Warning events"),
+		mcp.WithMIMEType("application/json"),
+	), h.getClusterHealth)
+}
+
+func (h *Handler) getClusterHealth(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	result, err := json.MarshalIndent(kube.GetClusterHealth(ctx, h.client), "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal cluster health: %w", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(result),
+		},
+	}, nil
+}
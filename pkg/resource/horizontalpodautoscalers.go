@@ -0,0 +1,118 @@
+package resource
+
+import (
+	"fmt"
+
+	autoscalingv2 "k8s.io/api/autoscaling/v2"
+)
+
+var horizontalPodAutoscalersKind = Kind{
+	Name:       "horizontalpodautoscalers",
+	Title:      "HorizontalPodAutoscalers",
+	GVR:        autoscalingv2.SchemeGroupVersion.WithResource("horizontalpodautoscalers"),
+	Namespaced: true,
+	Summarise:  Typed(summariseHorizontalPodAutoscaler),
+	Columns: []Column{
+		{Header: "REFERENCE", Field: "target"},
+		{Header: "MINPODS", Field: "minReplicas"},
+		{Header: "MAXPODS", Field: "maxReplicas"},
+		{Header: "REPLICAS", Field: "currentReplicas"},
+	},
+}
+
+func summariseHorizontalPodAutoscaler(hpa *autoscalingv2.HorizontalPodAutoscaler) map[string]interface{} {
+	minReplicas := int32(1)
+	if hpa.Spec.MinReplicas != nil {
+		minReplicas = *hpa.Spec.MinReplicas
+	}
+
+	current := map[string]autoscalingv2.MetricValueStatus{}
+	for _, status := range hpa.Status.CurrentMetrics {
+		name, value := metricStatus(status)
+		current[name] = value
+	}
+	var metrics []map[string]interface{}
+	for _, spec := range hpa.Spec.Metrics {
+		name, target := metricSpec(spec)
+		metric := map[string]interface{}{
+			"metric": name,
+			"target": formatMetricTarget(target),
+		}
+		if value, ok := current[name]; ok {
+			metric["current"] = formatMetricValue(value)
+		}
+		metrics = append(metrics, metric)
+	}
+
+	return map[string]interface{}{
+		"target":          hpa.Spec.ScaleTargetRef.Kind + "/" + hpa.Spec.ScaleTargetRef.Name,
+		"minReplicas":     minReplicas,
+		"maxReplicas":     hpa.Spec.MaxReplicas,
+		"currentReplicas": hpa.Status.CurrentReplicas,
+		"desiredReplicas": hpa.Status.DesiredReplicas,
+		"metrics":         metrics,
+	}
+}
+
+// metricSpec returns the name and target of a metric. Names are built the
+// same way as in metricStatus, e.g. "resource/cpu" or "pods/requests_per_second".
+func metricSpec(spec autoscalingv2.MetricSpec) (string, autoscalingv2.MetricTarget) {
+	switch {
+	case spec.Resource != nil:
+		return "resource/" + string(spec.Resource.Name), spec.Resource.Target
+	case spec.ContainerResource != nil:
+		return fmt.Sprintf("container/%s/%s", spec.ContainerResource.Container, spec.ContainerResource.Name), spec.ContainerResource.Target
+	case spec.Pods != nil:
+		return "pods/" + spec.Pods.Metric.Name, spec.Pods.Target
+	case spec.Object != nil:
+		return fmt.Sprintf("object/%s/%s/%s", spec.Object.DescribedObject.Kind, spec.Object.DescribedObject.Name, spec.Object.Metric.Name), spec.Object.Target
+	case spec.External != nil:
+		return "external/" + spec.External.Metric.Name, spec.External.Target
+	default:
+		return string(spec.Type), autoscalingv2.MetricTarget{}
+	}
+}
+
+// metricStatus returns the name and current value of a metric.
+func metricStatus(status autoscalingv2.MetricStatus) (string, autoscalingv2.MetricValueStatus) {
+	switch {
+	case status.Resource != nil:
+		return "resource/" + string(status.Resource.Name), status.Resource.Current
+	case status.ContainerResource != nil:
+		return fmt.Sprintf("container/%s/%s", status.ContainerResource.Container, status.ContainerResource.Name), status.ContainerResource.Current
+	case status.Pods != nil:
+		return "pods/" + status.Pods.Metric.Name, status.Pods.Current
+	case status.Object != nil:
+		return fmt.Sprintf("object/%s/%s/%s", status.Object.DescribedObject.Kind, status.Object.DescribedObject.Name, status.Object.Metric.Name), status.Object.Current
+	case status.External != nil:
+		return "external/" + status.External.Metric.Name, status.External.Current
+	default:
+		return string(status.Type), autoscalingv2.MetricValueStatus{}
+	}
+}
+
+func formatMetricTarget(target autoscalingv2.MetricTarget) string {
+	switch {
+	case target.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *target.AverageUtilization)
+	case target.AverageValue != nil:
+		return target.AverageValue.String() + " (average)"
+	case target.Value != nil:
+		return target.Value.String()
+	default:
+		return "<unknown>"
+	}
+}
+
+func formatMetricValue(value autoscalingv2.MetricValueStatus) string {
+	switch {
+	case value.AverageUtilization != nil:
+		return fmt.Sprintf("%d%%", *value.AverageUtilization)
+	case value.AverageValue != nil:
+		return value.AverageValue.String() + " (average)"
+	case value.Value != nil:
+		return value.Value.String()
+	default:
+		return "<unknown>"
+	}
+}
@@ -0,0 +1,84 @@
+package resource
+
+import (
+	"fmt"
+
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+var ingressesKind = Kind{
+	Name:       "ingresses",
+	Title:      "Ingresses",
+	GVR:        networkingv1.SchemeGroupVersion.WithResource("ingresses"),
+	Namespaced: true,
+	Summarise:  Typed(summariseIngress),
+	Columns: []Column{
+		{Header: "CLASS", Field: "ingressClass"},
+		{Header: "HOSTS", Field: "hosts"},
+		{Header: "ADDRESS", Field: "addresses"},
+	},
+}
+
+func summariseIngress(i *networkingv1.Ingress) map[string]interface{} {
+	hosts := []string{}
+	var paths []map[string]interface{}
+	for _, rule := range i.Spec.Rules {
+		if rule.Host != "" {
+			hosts = append(hosts, rule.Host)
+		}
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, p := range rule.HTTP.Paths {
+			paths = append(paths, map[string]interface{}{
+				"host":    rule.Host,
+				"path":    p.Path,
+				"backend": ingressBackendString(p.Backend),
+			})
+		}
+	}
+
+	var tls []map[string]interface{}
+	for _, t := range i.Spec.TLS {
+		tls = append(tls, map[string]interface{}{
+			"hosts":      t.Hosts,
+			"secretName": t.SecretName,
+		})
+	}
+
+	addresses := []string{}
+	for _, lb := range i.Status.LoadBalancer.Ingress {
+		if lb.IP != "" {
+			addresses = append(addresses, lb.IP)
+		} else if lb.Hostname != "" {
+			addresses = append(addresses, lb.Hostname)
+		}
+	}
+
+	summary := map[string]interface{}{
+		"hosts":     hosts,
+		"paths":     paths,
+		"tls":       tls,
+		"addresses": addresses,
+	}
+	if i.Spec.IngressClassName != nil {
+		summary["ingressClass"] = *i.Spec.IngressClassName
+	}
+	if i.Spec.DefaultBackend != nil {
+		summary["defaultBackend"] = ingressBackendString(*i.Spec.DefaultBackend)
+	}
+	return summary
+}
+
+func ingressBackendString(backend networkingv1.IngressBackend) string {
+	switch {
+	case backend.Service != nil && backend.Service.Port.Name != "":
+		return fmt.Sprintf("%s:%s", backend.Service.Name, backend.Service.Port.Name)
+	case backend.Service != nil:
+		return fmt.Sprintf("%s:%d", backend.Service.Name, backend.Service.Port.Number)
+	case backend.Resource != nil:
+		return fmt.Sprintf("%s/%s", backend.Resource.Kind, backend.Resource.Name)
+	default:
+		return ""
+	}
+}
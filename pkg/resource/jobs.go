@@ -0,0 +1,42 @@
+package resource
+
+import (
+	"time"
+
+	"github.com/idebeijer/kube-mcp-server/pkg/kube"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var jobsKind = Kind{
+	Name:       "jobs",
+	Title:      "Jobs",
+	GVR:        batchv1.SchemeGroupVersion.WithResource("jobs"),
+	Namespaced: true,
+	Summarise:  Typed(summariseJob),
+	Columns: []Column{
+		{Header: "STATUS", Field: "status"},
+		{Header: "COMPLETIONS", Field: "completions"},
+		{Header: "DURATION", Field: "duration"},
+	},
+}
+
+func summariseJob(j *batchv1.Job) map[string]interface{} {
+	status, reason := kube.GetJobStatus(j)
+	summary := map[string]interface{}{
+		"status":      status,
+		"completions": kube.GetJobCompletions(j),
+		"active":      j.Status.Active,
+		"failed":      j.Status.Failed,
+	}
+	if d := kube.GetJobDuration(j, time.Now()); d > 0 {
+		summary["duration"] = d.String()
+	}
+	if reason != "" {
+		summary["failureReason"] = reason
+	}
+	if owner := metav1.GetControllerOf(j); owner != nil && owner.Kind == "CronJob" {
+		summary["cronjob"] = owner.Name
+	}
+	return summary
+}
@@ -0,0 +1,142 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func (h *Handler) registerNamespaces(m *server.MCPServer) {
+	h.addResource(m, mcp.NewResource("k8s://cluster/namespaces", "Namespaces",
+		mcp.WithResourceDescription("List and view namespaces with phase, labels, resource quotas and workload counts"),
+		mcp.WithMIMEType("application/json"),
+	), h.getNamespaces)
+}
+
+func (h *Handler) getNamespaces(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	namespaces, err := h.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	counts, err := h.workloadCounts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	quotas, err := h.client.CoreV1().ResourceQuotas("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list resource quotas in all namespaces: %w", err)
+	}
+	quotasByNamespace := map[string][]map[string]interface{}{}
+	for _, q := range quotas.Items {
+		usage := map[string]string{}
+		for name, hard := range q.Status.Hard {
+			used := q.Status.Used[name]
+			usage[string(name)] = fmt.Sprintf("%s/%s", used.String(), hard.String())
+		}
+		quotasByNamespace[q.Namespace] = append(quotasByNamespace[q.Namespace], map[string]interface{}{
+			"name":  q.Name,
+			"usage": usage,
+		})
+	}
+
+	var summaries []map[string]interface{}
+	for _, ns := range namespaces.Items {
+		workloads := counts[ns.Name]
+		if workloads == nil {
+			workloads = map[string]int{}
+		}
+		summaries = append(summaries, map[string]interface{}{
+			"name":      ns.Name,
+			"phase":     ns.Status.Phase,
+			"labels":    ns.Labels,
+			"quotas":    quotasByNamespace[ns.Name],
+			"workloads": workloads,
+			"age":       time.Since(ns.CreationTimestamp.Time).Round(time.Second).String(),
+		})
+	}
+
+	result, err := json.MarshalIndent(map[string]interface{}{
+		"scope":      "Cluster",
+		"totalItems": len(summaries),
+		"namespaces": summaries,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal namespace summaries: %w", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(result),
+		},
+	}, nil
+}
+
+// workloadCounts counts the workloads of each kind per namespace.
+func (h *Handler) workloadCounts(ctx context.Context) (map[string]map[string]int, error) {
+	counts := map[string]map[string]int{}
+	add := func(namespace, kind string) {
+		if counts[namespace] == nil {
+			counts[namespace] = map[string]int{}
+		}
+		counts[namespace][kind]++
+	}
+
+	pods, err := h.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods in all namespaces: %w", err)
+	}
+	for _, p := range pods.Items {
+		add(p.Namespace, "pods")
+	}
+
+	deployments, err := h.client.AppsV1().Deployments("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list deployments in all namespaces: %w", err)
+	}
+	for _, d := range deployments.Items {
+		add(d.Namespace, "deployments")
+	}
+
+	statefulSets, err := h.client.AppsV1().StatefulSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list statefulsets in all namespaces: %w", err)
+	}
+	for _, s := range statefulSets.Items {
+		add(s.Namespace, "statefulsets")
+	}
+
+	daemonSets, err := h.client.AppsV1().DaemonSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daemonsets in all namespaces: %w", err)
+	}
+	for _, d := range daemonSets.Items {
+		add(d.Namespace, "daemonsets")
+	}
+
+	cronJobs, err := h.client.BatchV1().CronJobs("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cronjobs in all namespaces: %w", err)
+	}
+	for _, c := range cronJobs.Items {
+		add(c.Namespace, "cronjobs")
+	}
+
+	services, err := h.client.CoreV1().Services("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list services in all namespaces: %w", err)
+	}
+	for _, s := range services.Items {
+		add(s.Namespace, "services")
+	}
+
+	return counts, nil
+}
@@ -0,0 +1,106 @@
+package resource
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const nodeRoleLabelPrefix = "node-role.kubernetes.io/"
+
+func (h *Handler) registerNodes(m *server.MCPServer) {
+	h.addResource(m, mcp.NewResource("k8s://cluster/nodes", "Nodes",
+		mcp.WithResourceDescription("List and view nodes with roles, conditions, versions, capacity, taints and pod counts"),
+		mcp.WithMIMEType("application/json"),
+	), h.getNodes)
+}
+
+func (h *Handler) getNodes(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+	nodes, err := h.client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	pods, err := h.client.CoreV1().Pods("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods in all namespaces: %w", err)
+	}
+
+	podCounts := map[string]int{}
+	for _, p := range pods.Items {
+		if p.Spec.NodeName != "" && p.Status.Phase != corev1.PodSucceeded && p.Status.Phase != corev1.PodFailed {
+			podCounts[p.Spec.NodeName]++
+		}
+	}
+
+	var summaries []map[string]interface{}
+	for _, n := range nodes.Items {
+		conditions := map[string]string{}
+		for _, c := range n.Status.Conditions {
+			conditions[string(c.Type)] = string(c.Status)
+		}
+		taints := make([]string, 0, len(n.Spec.Taints))
+		for _, t := range n.Spec.Taints {
+			taints = append(taints, t.ToString())
+		}
+		summaries = append(summaries, map[string]interface{}{
+			"name":           n.Name,
+			"roles":          nodeRoles(&n),
+			"unschedulable":  n.Spec.Unschedulable,
+			"conditions":     conditions,
+			"kubeletVersion": n.Status.NodeInfo.KubeletVersion,
+			"os":             n.Status.NodeInfo.OperatingSystem,
+			"arch":           n.Status.NodeInfo.Architecture,
+			"osImage":        n.Status.NodeInfo.OSImage,
+			"capacity":       resourceListStrings(n.Status.Capacity),
+			"allocatable":    resourceListStrings(n.Status.Allocatable),
+			"taints":         taints,
+			"pods":           podCounts[n.Name],
+			"age":            time.Since(n.CreationTimestamp.Time).Round(time.Second).String(),
+		})
+	}
+
+	result, err := json.MarshalIndent(map[string]interface{}{
+		"scope":      "Cluster",
+		"totalItems": len(summaries),
+		"nodes":      summaries,
+	}, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal node summaries: %w", err)
+	}
+
+	return []mcp.ResourceContents{
+		mcp.TextResourceContents{
+			URI:      request.Params.URI,
+			MIMEType: "application/json",
+			Text:     string(result),
+		},
+	}, nil
+}
+
+// nodeRoles returns the roles from the node-role.kubernetes.io/<role> labels.
+func nodeRoles(node *corev1.Node) []string {
+	roles := []string{}
+	for label := range node.Labels {
+		if role, ok := strings.CutPrefix(label, nodeRoleLabelPrefix); ok && role != "" {
+			roles = append(roles, role)
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+func resourceListStrings(list corev1.ResourceList) map[string]string {
+	result := make(map[string]string, len(list))
+	for name, quantity := range list {
+		result[string(name)] = quantity.String()
+	}
+	return result
+}
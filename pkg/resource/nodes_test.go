@@ -0,0 +1,26 @@
+package resource
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodeRoles(t *testing.T) {
+	tests := []struct {
+		labels map[string]string
+		want   []string
+	}{
+		{map[string]string{"node-role.kubernetes.io/control-plane": "", "node-role.kubernetes.io/etcd": "true"}, []string{"control-plane", "etcd"}},
+		{map[string]string{"kubernetes.io/hostname": "worker-1"}, []string{}},
+		{map[string]string{"node-role.kubernetes.io/": ""}, []string{}},
+	}
+	for _, tt := range tests {
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Labels: tt.labels}}
+		if got := nodeRoles(node); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("nodeRoles(%v) = %v, want %v", tt.labels, got, tt.want)
+		}
+	}
+}
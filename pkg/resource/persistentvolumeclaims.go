@@ -0,0 +1,42 @@
+package resource
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+var persistentVolumeClaimsKind = Kind{
+	Name:       "persistentvolumeclaims",
+	Title:      "PersistentVolumeClaims",
+	GVR:        corev1.SchemeGroupVersion.WithResource("persistentvolumeclaims"),
+	Namespaced: true,
+	Summarise:  Typed(summarisePersistentVolumeClaim),
+	Columns: []Column{
+		{Header: "STATUS", Field: "phase"},
+		{Header: "VOLUME", Field: "volume"},
+		{Header: "CAPACITY", Field: "capacity"},
+		{Header: "ACCESS MODES", Field: "accessModes"},
+		{Header: "STORAGECLASS", Field: "storageClass"},
+	},
+}
+
+func summarisePersistentVolumeClaim(p *corev1.PersistentVolumeClaim) map[string]interface{} {
+	accessModes := make([]string, 0, len(p.Spec.AccessModes))
+	for _, mode := range p.Spec.AccessModes {
+		accessModes = append(accessModes, string(mode))
+	}
+	summary := map[string]interface{}{
+		"phase":       string(p.Status.Phase),
+		"volume":      p.Spec.VolumeName,
+		"accessModes": accessModes,
+	}
+	if capacity, ok := p.Status.Capacity[corev1.ResourceStorage]; ok {
+		summary["capacity"] = capacity.String()
+	}
+	if request, ok := p.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		summary["requested"] = request.String()
+	}
+	if p.Spec.StorageClassName != nil {
+		summary["storageClass"] = *p.Spec.StorageClassName
+	}
+	return summary
+}
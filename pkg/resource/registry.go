@@ -0,0 +1,221 @@
+package resource
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+// SummariseFunc returns the kind-specific fields of an object's summary. The
+// name, namespace and age fields are added by the registry.
+type SummariseFunc func(obj *unstructured.Unstructured) (map[string]interface{}, error)
+
+// Column maps a summary field to a column of tabular output.
+type Column struct {
+	Header string
+	Field  string
+}
+
+// Kind describes how a resource kind is listed and summarised.
+type Kind struct {
+	// Name is the plural used in resource URIs and as the key of the summary
+	// list, e.g. "deployments".
+	Name string
+	// Title is the human readable plural, e.g. "Deployments".
+	Title      string
+	GVR        schema.GroupVersionResource
+	Namespaced bool
+	Summarise  SummariseFunc
+	// Columns are shown between the NAMESPACE/NAME and AGE columns in tables.
+	Columns []Column
+}
+
+// Typed adapts a summariser of a typed API object to a SummariseFunc.
+func Typed[T any](fn func(obj *T) map[string]interface{}) SummariseFunc {
+	return func(u *unstructured.Unstructured) (map[string]interface{}, error) {
+		obj := new(T)
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+			return nil, fmt.Errorf("failed to convert %s %s: %w", u.GetKind(), u.GetName(), err)
+		}
+		return fn(obj), nil
+	}
+}
+
+// List lists the objects of the kind in the namespace, or all namespaces when
+// empty, and summarises them.
+func (k Kind) List(ctx context.Context, client dynamic.Interface, namespace string) ([]map[string]interface{}, error) {
+	var list *unstructured.UnstructuredList
+	var err error
+	if k.Namespaced {
+		list, err = client.Resource(k.GVR).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	} else {
+		list, err = client.Resource(k.GVR).List(ctx, metav1.ListOptions{})
+	}
+	if err != nil {
+		scope := "the cluster"
+		switch {
+		case k.Namespaced && namespace == "":
+			scope = "all namespaces"
+		case k.Namespaced:
+			scope = fmt.Sprintf("namespace '%s'", namespace)
+		}
+		return nil, fmt.Errorf("failed to list %s in %s: %w", k.Name, scope, err)
+	}
+
+	summaries := make([]map[string]interface{}, 0, len(list.Items))
+	for i := range list.Items {
+		obj := &list.Items[i]
+		summary := map[string]interface{}{}
+		if k.Summarise != nil {
+			if summary, err = k.Summarise(obj); err != nil {
+				return nil, err
+			}
+			if summary == nil {
+				summary = map[string]interface{}{}
+			}
+		}
+		summary["name"] = obj.GetName()
+		if k.Namespaced {
+			summary["namespace"] = obj.GetNamespace()
+		}
+		summary["age"] = time.Since(obj.GetCreationTimestamp().Time).Round(time.Second).String()
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// Table renders summaries of the kind as a table with the kind's columns.
+func (k Kind) Table(summaries []map[string]interface{}) string {
+	columns := []Column{{Header: "NAME", Field: "name"}}
+	if k.Namespaced {
+		columns = append([]Column{{Header: "NAMESPACE", Field: "namespace"}}, columns...)
+	}
+	columns = append(columns, k.Columns...)
+	columns = append(columns, Column{Header: "AGE", Field: "age"})
+
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	headers := make([]string, 0, len(columns))
+	for _, c := range columns {
+		headers = append(headers, c.Header)
+	}
+	_, _ = fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, s := range summaries {
+		values := make([]string, 0, len(columns))
+		for _, c := range columns {
+			values = append(values, formatCell(s[c.Field]))
+		}
+		_, _ = fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	_ = w.Flush()
+	return sb.String()
+}
+
+// formatCell renders a summary value for a table cell.
+func formatCell(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "<none>"
+	case string:
+		if v == "" {
+			return "<none>"
+		}
+		return v
+	case []string:
+		if len(v) == 0 {
+			return "<none>"
+		}
+		return strings.Join(v, ",")
+	case map[string]string:
+		if len(v) == 0 {
+			return "<none>"
+		}
+		pairs := make([]string, 0, len(v))
+		for key, val := range v {
+			pairs = append(pairs, key+"="+val)
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, ",")
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// Registry holds the kinds that are exposed as resources and summaries.
+type Registry struct {
+	mu    sync.RWMutex
+	kinds map[string]Kind
+	order []string
+}
+
+func NewRegistry() *Registry {
+	return &Registry{kinds: map[string]Kind{}}
+}
+
+// NewDefaultRegistry returns a registry with the built-in kinds registered.
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, k := range builtinKinds() {
+		if err := r.Register(k); err != nil {
+			panic(err)
+		}
+	}
+	return r
+}
+
+// Register adds a kind to the registry. Kind names are case-insensitive and
+// stored lowercased, so they must be unique regardless of case.
+func (r *Registry) Register(k Kind) error {
+	if k.Name == "" || k.GVR.Resource == "" {
+		return fmt.Errorf("kind must have a name and a resource")
+	}
+	if k.Title == "" {
+		k.Title = k.Name
+	}
+	k.Name = strings.ToLower(k.Name)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.kinds[k.Name]; ok {
+		return fmt.Errorf("kind %q is already registered", k.Name)
+	}
+	r.kinds[k.Name] = k
+	r.order = append(r.order, k.Name)
+	return nil
+}
+
+// Lookup returns the kind registered under the name.
+func (r *Registry) Lookup(name string) (Kind, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	k, ok := r.kinds[strings.ToLower(name)]
+	return k, ok
+}
+
+// Kinds returns the registered kinds in registration order.
+func (r *Registry) Kinds() []Kind {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	kinds := make([]Kind, 0, len(r.order))
+	for _, name := range r.order {
+		kinds = append(kinds, r.kinds[name])
+	}
+	return kinds
+}
+
+// Names returns the names of the registered kinds in registration order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return append([]string(nil), r.order...)
+}
@@ -0,0 +1,184 @@
+package resource
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/utils/ptr"
+)
+
+func TestRegistryRegister(t *testing.T) {
+	r := NewRegistry()
+	if err := r.Register(podsKind); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := r.Register(podsKind); err == nil {
+		t.Error("Register() of a duplicate kind succeeded")
+	}
+	if err := r.Register(Kind{Name: "widgets"}); err == nil {
+		t.Error("Register() of a kind without a resource succeeded")
+	}
+	if _, ok := r.Lookup("Pods"); !ok {
+		t.Error("Lookup() is not case-insensitive")
+	}
+}
+
+func TestRegistryRegisterExternalKind(t *testing.T) {
+	r := NewDefaultRegistry()
+	widgets := Kind{
+		Name:       "Widgets",
+		GVR:        schema.GroupVersionResource{Group: "example.com", Version: "v1", Resource: "widgets"},
+		Namespaced: true,
+	}
+	if err := r.Register(widgets); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	for _, name := range []string{"widgets", "Widgets", "WIDGETS"} {
+		k, ok := r.Lookup(name)
+		if !ok {
+			t.Errorf("Lookup(%q) found no kind", name)
+			continue
+		}
+		if k.Name != "widgets" || k.Title != "Widgets" {
+			t.Errorf("Lookup(%q) = name %q, title %q, want widgets, Widgets", name, k.Name, k.Title)
+		}
+	}
+	if names := r.Names(); names[len(names)-1] != "widgets" {
+		t.Errorf("Names() = %v, want widgets last", names)
+	}
+	if err := r.Register(Kind{Name: "PODS", GVR: podsKind.GVR}); err == nil {
+		t.Error("Register() of a kind differing only in case succeeded")
+	}
+}
+
+func TestKindListNilSummary(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		&corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "default"}},
+	)
+	gvr := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+
+	for name, summarise := range map[string]SummariseFunc{
+		"summarise func": func(*unstructured.Unstructured) (map[string]interface{}, error) { return nil, nil },
+		"typed":          Typed(func(*corev1.ConfigMap) map[string]interface{} { return nil }),
+	} {
+		t.Run(name, func(t *testing.T) {
+			r := NewRegistry()
+			if err := r.Register(Kind{Name: "configmaps", GVR: gvr, Namespaced: true, Summarise: summarise}); err != nil {
+				t.Fatalf("Register() error = %v", err)
+			}
+			k, _ := r.Lookup("configmaps")
+			summaries, err := k.List(context.Background(), client, "default")
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if len(summaries) != 1 || summaries[0]["name"] != "app" || summaries[0]["namespace"] != "default" {
+				t.Errorf("List() = %v, want the name and namespace of app", summaries)
+			}
+		})
+	}
+}
+
+func TestDefaultRegistry(t *testing.T) {
+	names := NewDefaultRegistry().Names()
+	if len(names) != len(builtinKinds()) {
+		t.Errorf("Names() = %v, want %d kinds", names, len(builtinKinds()))
+	}
+}
+
+func TestKindListAndTable(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	client := dynamicfake.NewSimpleDynamicClient(scheme,
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+			Spec:       corev1.PodSpec{NodeName: "node-1", Containers: []corev1.Container{{Name: "app"}}},
+			Status: corev1.PodStatus{
+				Phase:             corev1.PodRunning,
+				ContainerStatuses: []corev1.ContainerStatus{{Name: "app", Ready: true}},
+			},
+		},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "db", Namespace: "other"}},
+	)
+
+	summaries, err := podsKind.List(context.Background(), client, "default")
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(summaries) != 1 {
+		t.Fatalf("List() returned %d summaries, want 1", len(summaries))
+	}
+	s := summaries[0]
+	if s["name"] != "web" || s["namespace"] != "default" || s["ready"] != "1/1" || s["status"] != "Running" {
+		t.Errorf("unexpected summary: %v", s)
+	}
+
+	lines := strings.Split(strings.TrimSpace(podsKind.Table(summaries)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Table() returned %d lines, want 2", len(lines))
+	}
+	if got := strings.Fields(lines[0]); strings.Join(got, " ") != "NAMESPACE NAME READY STATUS NODE AGE" {
+		t.Errorf("header = %v", got)
+	}
+	if got := strings.Fields(lines[1]); got[0] != "default" || got[1] != "web" || got[4] != "node-1" {
+		t.Errorf("row = %v", got)
+	}
+}
+
+func TestSummaryKeysAreCamelCase(t *testing.T) {
+	camelCase := regexp.MustCompile(`^[a-z][a-zA-Z]*$`)
+	for _, k := range builtinKinds() {
+		for _, c := range k.Columns {
+			if !camelCase.MatchString(c.Field) {
+				t.Errorf("%s column %s has field %q, want camelCase", k.Name, c.Header, c.Field)
+			}
+		}
+	}
+
+	now := metav1.Now()
+	summaries := map[string]map[string]interface{}{
+		"configmap": summariseConfigMap(&corev1.ConfigMap{Data: map[string]string{"mode": "fast"}}),
+		"cronjob": summariseCronJob(&batchv1.CronJob{
+			Spec: batchv1.CronJobSpec{Schedule: "*/5 * * * *", TimeZone: ptr.To("Etc/UTC")},
+			Status: batchv1.CronJobStatus{
+				LastScheduleTime:   &now,
+				LastSuccessfulTime: &now,
+			},
+		}),
+		"cronjob with invalid schedule": summariseCronJob(&batchv1.CronJob{Spec: batchv1.CronJobSpec{Schedule: "never"}}),
+		"failed job": summariseJob(&batchv1.Job{Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"},
+		}}}),
+	}
+	for name, summary := range summaries {
+		for key := range summary {
+			if !camelCase.MatchString(key) {
+				t.Errorf("%s summary has key %q, want camelCase", name, key)
+			}
+		}
+	}
+	if got := summaries["configmap"]["totalKeys"]; got != 1 {
+		t.Errorf("configmap totalKeys = %v, want 1", got)
+	}
+	for _, key := range []string{"timeZone", "lastSchedule", "lastSuccessful", "nextSchedule"} {
+		if _, ok := summaries["cronjob"][key]; !ok {
+			t.Errorf("cronjob summary has no %s: %v", key, summaries["cronjob"])
+		}
+	}
+	if got := summaries["failed job"]["failureReason"]; got != "BackoffLimitExceeded" {
+		t.Errorf("job failureReason = %v, want BackoffLimitExceeded", got)
+	}
+	if _, ok := summaries["cronjob with invalid schedule"]["nextScheduleError"]; !ok {
+		t.Errorf("cronjob summary has no nextScheduleError: %v", summaries["cronjob with invalid schedule"])
+	}
+}
@@ -0,0 +1,36 @@
+package resource
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var replicaSetsKind = Kind{
+	Name:       "replicasets",
+	Title:      "ReplicaSets",
+	GVR:        appsv1.SchemeGroupVersion.WithResource("replicasets"),
+	Namespaced: true,
+	Summarise:  Typed(summariseReplicaSet),
+	Columns: []Column{
+		{Header: "DESIRED", Field: "desired"},
+		{Header: "CURRENT", Field: "current"},
+		{Header: "READY", Field: "ready"},
+		{Header: "OWNER", Field: "owner"},
+	},
+}
+
+func summariseReplicaSet(r *appsv1.ReplicaSet) map[string]interface{} {
+	desired := int32(1)
+	if r.Spec.Replicas != nil {
+		desired = *r.Spec.Replicas
+	}
+	summary := map[string]interface{}{
+		"desired": desired,
+		"current": r.Status.Replicas,
+		"ready":   r.Status.ReadyReplicas,
+	}
+	if owner := metav1.GetControllerOf(r); owner != nil {
+		summary["owner"] = owner.Kind + "/" + owner.Name
+	}
+	return summary
+}
@@ -1,23 +1,161 @@
 package resource
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/idebeijer/kube-mcp-server/pkg/toolset"
+	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/mark3labs/mcp-go/server"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
 )
 
 type Handler struct {
-	client *kubernetes.Clientset
+	client   *kubernetes.Clientset
+	dynamic  dynamic.Interface
+	registry *Registry
+	toolset  *toolset.Filter
+}
+
+type Option func(handler *Handler)
+
+// WithRegistry sets the registry of kinds exposed as resources. It defaults to
+// NewDefaultRegistry.
+func WithRegistry(registry *Registry) Option {
+	return func(h *Handler) {
+		h.registry = registry
+	}
+}
+
+// WithToolset hides the resources whose URI the filter doesn't allow.
+func WithToolset(filter *toolset.Filter) Option {
+	return func(h *Handler) {
+		h.toolset = filter
+	}
 }
 
-func NewHandler(client *kubernetes.Clientset) *Handler {
-	return &Handler{
-		client: client,
+func NewHandler(client *kubernetes.Clientset, dynamicClient dynamic.Interface, opts ...Option) *Handler {
+	h := &Handler{
+		client:  client,
+		dynamic: dynamicClient,
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	if h.registry == nil {
+		h.registry = NewDefaultRegistry()
+	}
+	return h
+}
+
+// builtinKinds returns the kinds registered by NewDefaultRegistry.
+func builtinKinds() []Kind {
+	return []Kind{
+		podsKind,
+		deploymentsKind,
+		servicesKind,
+		statefulSetsKind,
+		daemonSetsKind,
+		replicaSetsKind,
+		ingressesKind,
+		configMapsKind,
+		persistentVolumeClaimsKind,
+		horizontalPodAutoscalersKind,
+		jobsKind,
+		cronJobsKind,
+		storageClassesKind,
+		crdsKind,
 	}
 }
 
 func (h *Handler) Register(m *server.MCPServer) {
-	h.registerPods(m)
-	h.registerDeployments(m)
-	h.registerServices(m)
-	h.registerStatefulSets(m)
+	for _, k := range h.registry.Kinds() {
+		h.registerKind(m, k)
+	}
+	h.registerHealth(m)
+	h.registerNodes(m)
+	h.registerNamespaces(m)
+}
+
+// addResource registers a resource unless the toolset filter hides it.
+func (h *Handler) addResource(m *server.MCPServer, resource mcp.Resource, handler server.ResourceHandlerFunc) {
+	if !h.toolset.AllowsResource(resource.URI) {
+		return
+	}
+	m.AddResource(resource, handler)
+}
+
+// addResourceTemplate registers a resource template unless the toolset filter
+// hides it.
+func (h *Handler) addResourceTemplate(m *server.MCPServer, template mcp.ResourceTemplate, handler server.ResourceTemplateHandlerFunc) {
+	if !h.toolset.AllowsResource(template.URITemplate.Raw()) {
+		return
+	}
+	m.AddResourceTemplate(template, handler)
+}
+
+// registerKind exposes a kind as k8s://<name> and k8s://{namespace}/<name> when
+// it is namespaced, or as k8s://cluster/<name> when it is cluster-scoped.
+func (h *Handler) registerKind(m *server.MCPServer, k Kind) {
+	plural := strings.ToLower(k.Title)
+	if !k.Namespaced {
+		h.addResource(m, mcp.NewResource("k8s://cluster/"+k.Name, k.Title,
+			mcp.WithResourceDescription(fmt.Sprintf("List and view %s", plural)),
+			mcp.WithMIMEType("application/json"),
+		), h.kindHandler(k))
+		return
+	}
+
+	h.addResource(m, mcp.NewResource("k8s://"+k.Name, k.Title,
+		mcp.WithResourceDescription(fmt.Sprintf("List and view %s across all namespaces", plural)),
+		mcp.WithMIMEType("application/json"),
+	), h.kindHandler(k))
+	h.addResourceTemplate(m, mcp.NewResourceTemplate(
+		"k8s://{namespace}/"+k.Name,
+		k.Title+" in namespace",
+		mcp.WithTemplateDescription(fmt.Sprintf("List and view %s in a specific namespace", plural)),
+		mcp.WithTemplateMIMEType("application/json"),
+	), server.ResourceTemplateHandlerFunc(h.kindHandler(k)))
+}
+
+func (h *Handler) kindHandler(k Kind) server.ResourceHandlerFunc {
+	return func(ctx context.Context, request mcp.ReadResourceRequest) ([]mcp.ResourceContents, error) {
+		uri := request.Params.URI
+
+		var namespace string
+		scope := "Cluster"
+		if k.Namespaced {
+			scope = "All namespaces"
+			if uri != "k8s://"+k.Name {
+				namespace, _ = ExtractNamespaceFromURI(uri)
+				scope = fmt.Sprintf("Namespace: %s", namespace)
+			}
+		}
+
+		summaries, err := k.List(ctx, h.dynamic, namespace)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := json.MarshalIndent(map[string]interface{}{
+			"scope":      scope,
+			"namespace":  namespace,
+			"totalItems": len(summaries),
+			k.Name:       summaries,
+		}, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal %s summaries: %w", k.Name, err)
+		}
+
+		return []mcp.ResourceContents{
+			mcp.TextResourceContents{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(result),
+			},
+		}, nil
+	}
 }
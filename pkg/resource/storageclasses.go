@@ -0,0 +1,37 @@
+package resource
+
+import (
+	storagev1 "k8s.io/api/storage/v1"
+)
+
+const defaultStorageClassAnnotation = "storageclass.kubernetes.io/is-default-class"
+
+var storageClassesKind = Kind{
+	Name:      "storageclasses",
+	Title:     "StorageClasses",
+	GVR:       storagev1.SchemeGroupVersion.WithResource("storageclasses"),
+	Summarise: Typed(summariseStorageClass),
+	Columns: []Column{
+		{Header: "PROVISIONER", Field: "provisioner"},
+		{Header: "DEFAULT", Field: "default"},
+		{Header: "RECLAIMPOLICY", Field: "reclaimPolicy"},
+		{Header: "VOLUMEBINDINGMODE", Field: "volumeBindingMode"},
+		{Header: "ALLOWVOLUMEEXPANSION", Field: "allowVolumeExpansion"},
+	},
+}
+
+func summariseStorageClass(sc *storagev1.StorageClass) map[string]interface{} {
+	summary := map[string]interface{}{
+		"provisioner":          sc.Provisioner,
+		"default":              sc.Annotations[defaultStorageClassAnnotation] == "true",
+		"allowVolumeExpansion": sc.AllowVolumeExpansion != nil && *sc.AllowVolumeExpansion,
+		"parameters":           sc.Parameters,
+	}
+	if sc.ReclaimPolicy != nil {
+		summary["reclaimPolicy"] = string(*sc.ReclaimPolicy)
+	}
+	if sc.VolumeBindingMode != nil {
+		summary["volumeBindingMode"] = string(*sc.VolumeBindingMode)
+	}
+	return summary
+}
@@ -0,0 +1,202 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func (h *Handler) registerApply(m *server.MCPServer) {
+	h.addTool(m, mcp.NewTool("apply_manifest",
+		mcp.WithDescription("Apply inline YAML or JSON manifests to the cluster using server-side apply. Multiple YAML documents separated by '---' are applied in order."),
+		mcp.WithString("manifest",
+			mcp.Description("YAML or JSON content of the objects to apply (multiple YAML documents and kind: List are supported)"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace for namespaced objects that don't set metadata.namespace (defaults to the server's default namespace)"),
+		),
+		mcp.WithString("field_manager",
+			mcp.Description(fmt.Sprintf("Field manager name to apply as (defaults to the server's configured field manager, '%s' unless overridden)", defaultFieldManager)),
+		),
+		mcp.WithBoolean("force_conflicts",
+			mcp.Description("Take ownership of fields managed by other field managers instead of failing on conflicts"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Run a server-side dry-run without persisting any changes"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithOutputSchema[applyManifestResult](),
+	), mcp.NewTypedToolHandler[ApplyManifestArgs](h.applyManifestHandler()))
+}
+
+type ApplyManifestArgs struct {
+	Manifest       string `json:"manifest"`
+	Namespace      string `json:"namespace,omitempty"`
+	FieldManager   string `json:"field_manager,omitempty"`
+	ForceConflicts bool   `json:"force_conflicts"`
+	DryRun         bool   `json:"dry_run"`
+}
+
+type applyOutcome string
+
+const (
+	applyCreated    applyOutcome = "created"
+	applyConfigured applyOutcome = "configured"
+	applyUnchanged  applyOutcome = "unchanged"
+	applyFailed     applyOutcome = "failed"
+)
+
+type applyResult struct {
+	Ref       string       `json:"ref"`
+	Outcome   applyOutcome `json:"outcome"`
+	Error     string       `json:"error,omitempty"`
+	Conflicts []string     `json:"conflicts,omitempty"`
+}
+
+// applyManifestResult is the structured output of apply_manifest.
+type applyManifestResult struct {
+	FieldManager   string        `json:"fieldManager"`
+	ForceConflicts bool          `json:"forceConflicts"`
+	DryRun         bool          `json:"dryRun"`
+	Failed         int           `json:"failed"`
+	Objects        []applyResult `json:"objects"`
+}
+
+func (h *Handler) applyManifestHandler() mcp.TypedToolHandlerFunc[ApplyManifestArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args ApplyManifestArgs,
+	) (*mcp.CallToolResult, error) {
+		objects, err := decodeManifest(args.Manifest)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid manifest", err), nil
+		}
+
+		fieldManager := args.FieldManager
+		if fieldManager == "" {
+			fieldManager = h.fieldManager
+		}
+		opts := metav1.ApplyOptions{
+			FieldManager: fieldManager,
+			Force:        args.ForceConflicts,
+		}
+		if args.DryRun {
+			opts.DryRun = []string{metav1.DryRunAll}
+		}
+
+		structured := applyManifestResult{
+			FieldManager:   fieldManager,
+			ForceConflicts: args.ForceConflicts,
+			DryRun:         args.DryRun,
+			Objects:        []applyResult{},
+		}
+		for _, obj := range objects {
+			result := h.applyObject(ctx, obj, args.Namespace, opts)
+			if result.Outcome == applyFailed {
+				structured.Failed++
+			}
+			structured.Objects = append(structured.Objects, result)
+		}
+
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("Server-side apply (field manager: %s", fieldManager))
+		if args.ForceConflicts {
+			sb.WriteString(", force conflicts")
+		}
+		if args.DryRun {
+			sb.WriteString(", dry run")
+		}
+		sb.WriteString(fmt.Sprintf("): %d object(s), %d failed\n\n", len(structured.Objects), structured.Failed))
+		for _, r := range structured.Objects {
+			if r.Outcome == applyFailed {
+				sb.WriteString(fmt.Sprintf("%s %s: %s\n", r.Ref, r.Outcome, r.Error))
+			} else {
+				sb.WriteString(fmt.Sprintf("%s %s\n", r.Ref, r.Outcome))
+			}
+			for _, c := range r.Conflicts {
+				sb.WriteString(fmt.Sprintf("  conflict: %s\n", c))
+			}
+		}
+
+		result := mcp.NewToolResultStructured(structured, sb.String())
+		result.IsError = structured.Failed > 0
+		return result, nil
+	}
+}
+
+// applyObject applies a single object and classifies the outcome by comparing
+// it against the live object fetched beforehand.
+func (h *Handler) applyObject(ctx context.Context, obj *unstructured.Unstructured, namespace string, opts metav1.ApplyOptions) applyResult {
+	ri, mapping, err := h.resourceInterfaceFor(obj, namespace)
+	if err != nil {
+		return applyResult{Ref: fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName()), Outcome: applyFailed, Error: err.Error()}
+	}
+	ref := objectRef(mapping, obj)
+
+	live, err := ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return applyResult{Ref: ref, Outcome: applyFailed, Error: fmt.Sprintf("failed to get live object: %v", err)}
+	}
+	if err != nil {
+		live = nil
+	}
+
+	applied, err := ri.Apply(ctx, obj.GetName(), obj, opts)
+	if err != nil {
+		return applyResult{Ref: ref, Outcome: applyFailed, Error: err.Error(), Conflicts: fieldConflicts(err)}
+	}
+
+	switch {
+	case live == nil:
+		return applyResult{Ref: ref, Outcome: applyCreated}
+	case len(opts.DryRun) > 0:
+		// Dry-run responses keep the live resourceVersion, so compare content instead.
+		if reflect.DeepEqual(normalizeObject(live), normalizeObject(applied)) {
+			return applyResult{Ref: ref, Outcome: applyUnchanged}
+		}
+		return applyResult{Ref: ref, Outcome: applyConfigured}
+	case live.GetResourceVersion() == applied.GetResourceVersion():
+		return applyResult{Ref: ref, Outcome: applyUnchanged}
+	default:
+		return applyResult{Ref: ref, Outcome: applyConfigured}
+	}
+}
+
+// fieldConflicts extracts the field manager conflicts from a failed apply.
+func fieldConflicts(err error) []string {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil {
+		return nil
+	}
+
+	var conflicts []string
+	for _, cause := range status.Status().Details.Causes {
+		if cause.Type != metav1.CauseTypeFieldManagerConflict {
+			continue
+		}
+		conflicts = append(conflicts, cause.Message)
+	}
+	return conflicts
+}
+
+// normalizeObject returns a copy of the object without server-managed fields
+// that change independently of the desired state.
+func normalizeObject(obj *unstructured.Unstructured) map[string]interface{} {
+	normalized := obj.DeepCopy()
+	normalized.SetManagedFields(nil)
+	normalized.SetResourceVersion("")
+	normalized.SetGeneration(0)
+	unstructured.RemoveNestedField(normalized.Object, "status")
+	return normalized.Object
+}
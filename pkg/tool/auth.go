@@ -0,0 +1,194 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// canIResult is the structured output of can_i: the answer to an access check
+// when a verb was given, otherwise the effective rules in the namespace.
+type canIResult struct {
+	Access *accessCheck                              `json:"access,omitempty"`
+	Rules  *authorizationv1.SubjectRulesReviewStatus `json:"rules,omitempty"`
+	// Namespace is the namespace the rules were reviewed in.
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type accessCheck struct {
+	Allowed         bool                               `json:"allowed"`
+	Attributes      authorizationv1.ResourceAttributes `json:"attributes"`
+	Reason          string                             `json:"reason,omitempty"`
+	EvaluationError string                             `json:"evaluationError,omitempty"`
+}
+
+func (h *Handler) registerAuth(m *server.MCPServer) {
+	h.addTool(m, mcp.NewTool("can_i",
+		mcp.WithDescription("Check whether the server's Kubernetes identity may perform an action (SelfSubjectAccessReview), or list its effective rules in a namespace (SelfSubjectRulesReview) when no verb is given"),
+		mcp.WithString("verb",
+			mcp.Description("Verb to check (e.g., get, list, create, delete, patch); leave empty to list all effective rules"),
+		),
+		mcp.WithString("resource",
+			mcp.Description("Resource type to check (e.g., pods, deployments.apps, pods/log); required when verb is set"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of a specific resource to check"),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to check in (defaults to the server's default namespace; ignored for cluster-scoped resources)"),
+		),
+		mcp.WithBoolean("all_namespaces",
+			mcp.Description("Check the permission across all namespaces"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithOutputSchema[canIResult](),
+	), mcp.NewTypedToolHandler[CanIArgs](h.canIHandler()))
+}
+
+type CanIArgs struct {
+	Verb          string `json:"verb,omitempty"`
+	Resource      string `json:"resource,omitempty"`
+	Name          string `json:"name,omitempty"`
+	Namespace     string `json:"namespace,omitempty"`
+	AllNamespaces bool   `json:"all_namespaces"`
+}
+
+func (h *Handler) canIHandler() mcp.TypedToolHandlerFunc[CanIArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args CanIArgs,
+	) (*mcp.CallToolResult, error) {
+		namespace := args.Namespace
+		if namespace == "" {
+			namespace = h.defaultNamespace
+		}
+
+		if args.Verb == "" {
+			review, err := h.rulesReview(ctx, namespace)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("rules review failed", err), nil
+			}
+			return mcp.NewToolResultStructured(canIResult{Rules: &review.Status, Namespace: namespace}, formatRulesReview(namespace, review)), nil
+		}
+		if args.Resource == "" {
+			return mcp.NewToolResultError("resource must be specified when verb is set"), nil
+		}
+
+		attrs := &authorizationv1.ResourceAttributes{
+			Verb: args.Verb,
+			Name: args.Name,
+		}
+		resource, subresource, _ := strings.Cut(args.Resource, "/")
+		attrs.Subresource = subresource
+		if resource == "*" {
+			attrs.Resource, attrs.Group = "*", "*"
+		} else {
+			mapping, err := h.resolveResource(resource)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to resolve resource", err), nil
+			}
+			attrs.Group = mapping.Resource.Group
+			attrs.Resource = mapping.Resource.Resource
+			if mapping.Scope.Name() == meta.RESTScopeNameNamespace && !args.AllNamespaces {
+				attrs.Namespace = namespace
+			}
+		}
+
+		review, err := h.client.AuthorizationV1().SelfSubjectAccessReviews().Create(ctx, &authorizationv1.SelfSubjectAccessReview{
+			Spec: authorizationv1.SelfSubjectAccessReviewSpec{ResourceAttributes: attrs},
+		}, metav1.CreateOptions{})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("access review failed", err), nil
+		}
+
+		var sb strings.Builder
+		answer := "no"
+		if review.Status.Allowed {
+			answer = "yes"
+		}
+		sb.WriteString(fmt.Sprintf("%s: %s %s", answer, attrs.Verb, describeAttributes(attrs)))
+		if review.Status.Reason != "" {
+			sb.WriteString(fmt.Sprintf("\nReason: %s", review.Status.Reason))
+		}
+		if review.Status.EvaluationError != "" {
+			sb.WriteString(fmt.Sprintf("\nEvaluation error: %s", review.Status.EvaluationError))
+		}
+		access := &accessCheck{
+			Allowed:         review.Status.Allowed,
+			Attributes:      *attrs,
+			Reason:          review.Status.Reason,
+			EvaluationError: review.Status.EvaluationError,
+		}
+		return mcp.NewToolResultStructured(canIResult{Access: access}, sb.String()), nil
+	}
+}
+
+func (h *Handler) rulesReview(ctx context.Context, namespace string) (*authorizationv1.SelfSubjectRulesReview, error) {
+	return h.client.AuthorizationV1().SelfSubjectRulesReviews().Create(ctx, &authorizationv1.SelfSubjectRulesReview{
+		Spec: authorizationv1.SelfSubjectRulesReviewSpec{Namespace: namespace},
+	}, metav1.CreateOptions{})
+}
+
+func describeAttributes(attrs *authorizationv1.ResourceAttributes) string {
+	resource := attrs.Resource
+	if attrs.Group != "" && attrs.Group != "*" {
+		resource += "." + attrs.Group
+	}
+	if attrs.Subresource != "" {
+		resource += "/" + attrs.Subresource
+	}
+	if attrs.Name != "" {
+		resource += "/" + attrs.Name
+	}
+	if attrs.Namespace != "" {
+		return fmt.Sprintf("%s in namespace %s", resource, attrs.Namespace)
+	}
+	return resource + " (cluster-wide)"
+}
+
+func formatRulesReview(namespace string, review *authorizationv1.SelfSubjectRulesReview) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Effective rules in namespace %s", namespace))
+	if review.Status.Incomplete {
+		sb.WriteString(" (incomplete: the authorizer could not list all rules")
+		if review.Status.EvaluationError != "" {
+			sb.WriteString(": " + review.Status.EvaluationError)
+		}
+		sb.WriteString(")")
+	}
+	sb.WriteString("\n\n")
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "RESOURCES\tNON-RESOURCE URLS\tRESOURCE NAMES\tVERBS")
+	for _, rule := range review.Status.ResourceRules {
+		resources := make([]string, 0, len(rule.Resources))
+		for _, r := range rule.Resources {
+			for _, g := range rule.APIGroups {
+				if g == "" {
+					resources = append(resources, r)
+				} else {
+					resources = append(resources, r+"."+g)
+				}
+			}
+		}
+		_, _ = fmt.Fprintf(w, "%s\t[]\t%s\t%s\n",
+			strings.Join(resources, ","), bracketList(rule.ResourceNames), bracketList(rule.Verbs))
+	}
+	for _, rule := range review.Status.NonResourceRules {
+		_, _ = fmt.Fprintf(w, "\t%s\t[]\t%s\n", bracketList(rule.NonResourceURLs), bracketList(rule.Verbs))
+	}
+	_ = w.Flush()
+	return sb.String()
+}
+
+func bracketList(list []string) string {
+	return "[" + strings.Join(list, " ") + "]"
+}
@@ -0,0 +1,422 @@
+package tool
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultConfirmTokenTTL is how long a confirm_token stays valid.
+	defaultConfirmTokenTTL = 5 * time.Minute
+	// maxPreviewBytes caps the dry-run output shown when asking for confirmation.
+	maxPreviewBytes = 4 * 1024
+	confirmTokenArg = "confirm_token"
+)
+
+// confirmableTools are the tools that can make destructive changes and so
+// accept a confirm_token.
+var confirmableTools = map[string]bool{
+	"kubectl_delete":  true,
+	"kubectl_apply":   true,
+	"kubectl_generic": true,
+	"apply_manifest":  true,
+}
+
+// kubectlWriteCommands are the kubectl subcommands that change the cluster.
+// rollout only writes for some of its subcommands, see destructiveAction.
+var kubectlWriteCommands = map[string]bool{
+	"annotate": true, "apply": true, "autoscale": true, "certificate": true,
+	"cordon": true, "create": true, "delete": true, "drain": true,
+	"expose": true, "label": true, "patch": true, "replace": true,
+	"rollout": true, "run": true, "scale": true, "set": true,
+	"taint": true, "uncordon": true,
+}
+
+// WithDestructiveConfirmation sets whether destructive tool calls, such as
+// deletes, drains and forced applies, must be confirmed before they run.
+func WithDestructiveConfirmation(enabled bool) Option {
+	return func(h *Handler) {
+		h.destructiveConfirmation = enabled
+	}
+}
+
+// WithConfirmTokenTTL sets how long a confirm_token handed to clients without
+// elicitation support stays valid.
+func WithConfirmTokenTTL(ttl time.Duration) Option {
+	return func(h *Handler) {
+		if ttl > 0 {
+			h.confirmations.ttl = ttl
+		}
+	}
+}
+
+// confirmAction describes a tool call that needs confirmation.
+type confirmAction struct {
+	// reason says why the call needs confirmation.
+	reason string
+	// command is the exact command the call runs.
+	command string
+	// preview returns what the call would change, usually from a server-side
+	// dry-run. It may be nil.
+	preview func(ctx context.Context) string
+}
+
+// destructiveAction returns what a destructive tool call would do, or nil when
+// the call isn't destructive.
+func (h *Handler) destructiveAction(toolName string, req mcp.CallToolRequest, handler server.ToolHandlerFunc) *confirmAction {
+	switch toolName {
+	case "kubectl_delete":
+		var args KubectlDeleteArgs
+		if err := req.BindArguments(&args); err != nil {
+			return nil
+		}
+		reason := "deletes resources"
+		switch {
+		case args.All:
+			reason = fmt.Sprintf("deletes all %s in the namespace", args.Resource)
+		case args.Force:
+			reason = "force deletes resources without waiting for graceful termination"
+		}
+		return h.kubectlAction(reason, kubectlDeleteCommand(args))
+
+	case "kubectl_apply":
+		var args KubectlApplyArgs
+		if err := req.BindArguments(&args); err != nil || !args.Force || args.DryRun {
+			return nil
+		}
+		return h.kubectlAction("applies with --force, which deletes and re-creates resources that fail to update", kubectlApplyCommand(args))
+
+	case "kubectl_generic":
+		var args KubectlGenericArgs
+		if err := req.BindArguments(&args); err != nil {
+			return nil
+		}
+		cmdArgs := []string(args.Args)
+		inv := kubectlCommand(cmdArgs)
+		command, flags := inv.command, inv.flags
+		if dryRun, ok := flags["dry-run"]; ok && dryRun != "none" {
+			return nil
+		}
+		if !kubectlWriteCommands[command] {
+			return nil
+		}
+		reason := fmt.Sprintf("runs kubectl %s, which changes the cluster", command)
+		switch {
+		case command == "rollout" && inv.subcommand != "restart" && inv.subcommand != "undo" && inv.subcommand != "pause" && inv.subcommand != "resume":
+			return nil
+		case command == "delete":
+			reason = "deletes resources"
+		case command == "drain":
+			reason = "evicts all pods from the node"
+		case command == "scale" && flags["replicas"] == "0":
+			reason = "scales to zero replicas"
+		case command == "apply" && flags["force"] == "true":
+			reason = "applies with --force, which deletes and re-creates resources that fail to update"
+		}
+		return h.kubectlAction(reason, cmdArgs)
+
+	case "apply_manifest":
+		var args ApplyManifestArgs
+		if err := req.BindArguments(&args); err != nil || !args.ForceConflicts || args.DryRun {
+			return nil
+		}
+		fieldManager := args.FieldManager
+		if fieldManager == "" {
+			fieldManager = h.fieldManager
+		}
+		return &confirmAction{
+			reason:  "applies with force_conflicts, taking ownership of fields managed by others",
+			command: fmt.Sprintf("server-side apply of the manifest with force_conflicts as field manager %q", fieldManager),
+			preview: func(ctx context.Context) string {
+				dryRun := req
+				dryRun.Params.Arguments = withArgument(req.GetArguments(), "dry_run", true)
+				result, err := handler(ctx, dryRun)
+				if err != nil {
+					return fmt.Sprintf("dry-run failed: %v", err)
+				}
+				return resultText(result)
+			},
+		}
+	}
+	return nil
+}
+
+// kubectlAction describes a kubectl command, previewed with a server-side
+// dry-run of the same command.
+func (h *Handler) kubectlAction(reason string, cmdArgs []string) *confirmAction {
+	return &confirmAction{
+		reason:  reason,
+		command: "kubectl " + shellJoin(cmdArgs),
+		preview: func(ctx context.Context) string {
+			res, err := h.runKubectl(ctx, withServerDryRun(cmdArgs)...)
+			if err != nil {
+				return fmt.Sprintf("dry-run failed: %s", strings.TrimSpace(res.Stderr+"\n"+err.Error()))
+			}
+			return strings.TrimSpace(res.Stdout + "\n" + res.Stderr)
+		},
+	}
+}
+
+// withServerDryRun adds --dry-run=server to kubectl arguments, before any
+// arguments for a command run in a container.
+func withServerDryRun(args []string) []string {
+	out := make([]string, 0, len(args)+1)
+	for i, arg := range args {
+		if arg == "--" {
+			out = append(out, "--dry-run=server")
+			return append(out, args[i:]...)
+		}
+		out = append(out, arg)
+	}
+	return append(out, "--dry-run=server")
+}
+
+// requireConfirmation wraps a tool handler so destructive calls run only after
+// they are confirmed.
+func (h *Handler) requireConfirmation(toolName string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if !h.destructiveConfirmation || !confirmableTools[toolName] {
+		return handler
+	}
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if confirmed(ctx) {
+			return handler(ctx, req)
+		}
+		action := h.destructiveAction(toolName, req, handler)
+		if action == nil {
+			return handler(ctx, withoutArgument(req, confirmTokenArg))
+		}
+		return h.confirm(ctx, toolName, req, action, handler)
+	}
+}
+
+type confirmedKey struct{}
+
+// confirmed reports whether the call was already confirmed, so it isn't
+// confirmed twice when both the policy and the destructive check ask for it.
+func confirmed(ctx context.Context) bool {
+	ok, _ := ctx.Value(confirmedKey{}).(bool)
+	return ok
+}
+
+// confirm runs the handler once the call is confirmed. Clients that support
+// elicitation are asked directly; other clients get a preview and a
+// confirm_token to pass back with the same arguments.
+func (h *Handler) confirm(ctx context.Context, toolName string, req mcp.CallToolRequest, action *confirmAction, handler server.ToolHandlerFunc) (*mcp.CallToolResult, error) {
+	key, err := confirmKey(toolName, req)
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to prepare confirmation", err), nil
+	}
+
+	if token, _ := req.GetArguments()[confirmTokenArg].(string); token != "" {
+		if err := h.confirmations.redeem(token, key); err != nil {
+			return mcp.NewToolResultError(fmt.Sprintf("%s was not run: %v", toolName, err)), nil
+		}
+		log.Info().Str("tool", toolName).Str("command", action.command).Msg("Running tool call confirmed with token")
+		return handler(context.WithValue(ctx, confirmedKey{}, true), withoutArgument(req, confirmTokenArg))
+	}
+
+	preview := ""
+	if action.preview != nil {
+		previewCtx, cancel := h.previewContext(ctx, toolName, req)
+		preview = truncateText(action.preview(previewCtx), maxPreviewBytes)
+		cancel()
+	}
+
+	accepted, err := elicitConfirmation(ctx, confirmMessage(toolName, action, preview))
+	switch {
+	case errors.Is(err, errElicitationUnavailable):
+	case err != nil:
+		return mcp.NewToolResultErrorFromErr("failed to ask for confirmation", err), nil
+	case !accepted:
+		log.Info().Str("tool", toolName).Str("command", action.command).Msg("Tool call declined by the user")
+		return mcp.NewToolResultError(fmt.Sprintf("%s was not run: the user did not confirm %s", toolName, action.command)), nil
+	default:
+		log.Info().Str("tool", toolName).Str("command", action.command).Msg("Running tool call confirmed by the user")
+		return handler(context.WithValue(ctx, confirmedKey{}, true), req)
+	}
+
+	token, expires := h.confirmations.issue(key)
+	text := confirmMessage(toolName, action, preview) + fmt.Sprintf(
+		"\n\nNothing was changed. To proceed, call %s again with the same arguments and %s %q before %s.",
+		toolName, confirmTokenArg, token, expires.UTC().Format(time.RFC3339))
+	return mcp.NewToolResultError(text), nil
+}
+
+// previewContext bounds the dry-run preview of a call by the call's timeout,
+// as previews run before the call's own timeout applies.
+func (h *Handler) previewContext(ctx context.Context, toolName string, req mcp.CallToolRequest) (context.Context, context.CancelFunc) {
+	timeout, err := h.timeoutFor(toolName, req.GetArguments())
+	if err != nil {
+		timeout = h.defaultTimeout(toolName)
+	}
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+func confirmMessage(toolName string, action *confirmAction, preview string) string {
+	msg := fmt.Sprintf("Confirm %s: this call %s.\n\nCommand: %s", toolName, action.reason, action.command)
+	if preview != "" {
+		msg += "\n\nServer-side dry-run:\n" + preview
+	}
+	return msg
+}
+
+var errElicitationUnavailable = errors.New("client does not support elicitation")
+
+// elicitConfirmation asks the user to confirm through MCP elicitation. It
+// returns errElicitationUnavailable when the client didn't declare support.
+func elicitConfirmation(ctx context.Context, message string) (bool, error) {
+	srv := server.ServerFromContext(ctx)
+	session, ok := server.ClientSessionFromContext(ctx).(server.SessionWithClientInfo)
+	if srv == nil || !ok || session.GetClientCapabilities().Elicitation == nil {
+		return false, errElicitationUnavailable
+	}
+
+	result, err := srv.RequestElicitation(ctx, mcp.ElicitationRequest{
+		Params: mcp.ElicitationParams{
+			Message: message,
+			RequestedSchema: map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"confirm": map[string]interface{}{
+						"type":        "boolean",
+						"title":       "Run this command",
+						"description": "Check to run the command shown above",
+					},
+				},
+				"required": []string{"confirm"},
+			},
+		},
+	})
+	if errors.Is(err, server.ErrElicitationNotSupported) {
+		return false, errElicitationUnavailable
+	}
+	if err != nil {
+		return false, err
+	}
+	if result.Action != mcp.ElicitationResponseActionAccept {
+		return false, nil
+	}
+	content, _ := result.Content.(map[string]interface{})
+	accepted, _ := content["confirm"].(bool)
+	return accepted, nil
+}
+
+// confirmKey identifies a call by its tool and arguments, without the token
+// and the timeout, which don't change what the call does.
+func confirmKey(toolName string, req mcp.CallToolRequest) (string, error) {
+	args := withoutArgument(withoutArgument(req, confirmTokenArg), timeoutArg).GetArguments()
+	data, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	return toolName + " " + string(data), nil
+}
+
+// withoutArgument returns the request without the named argument.
+func withoutArgument(req mcp.CallToolRequest, name string) mcp.CallToolRequest {
+	args := req.GetArguments()
+	if _, ok := args[name]; !ok {
+		return req
+	}
+	stripped := make(map[string]interface{}, len(args))
+	for k, v := range args {
+		if k != name {
+			stripped[k] = v
+		}
+	}
+	req.Params.Arguments = stripped
+	return req
+}
+
+func withArgument(args map[string]interface{}, name string, value interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(args)+1)
+	for k, v := range args {
+		out[k] = v
+	}
+	out[name] = value
+	return out
+}
+
+// resultText joins the text content of a tool result.
+func resultText(result *mcp.CallToolResult) string {
+	if result == nil {
+		return ""
+	}
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// confirmStore keeps the confirm_tokens handed out and the call each confirms.
+type confirmStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	pending map[string]pendingConfirmation
+}
+
+type pendingConfirmation struct {
+	key     string
+	expires time.Time
+}
+
+func newConfirmStore(ttl time.Duration) *confirmStore {
+	return &confirmStore{ttl: ttl, now: time.Now, pending: map[string]pendingConfirmation{}}
+}
+
+// issue returns a new token confirming the call identified by key.
+func (s *confirmStore) issue(key string) (string, time.Time) {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	token := hex.EncodeToString(b)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	for t, p := range s.pending {
+		if now.After(p.expires) {
+			delete(s.pending, t)
+		}
+	}
+	expires := now.Add(s.ttl)
+	s.pending[token] = pendingConfirmation{key: key, expires: expires}
+	return token, expires
+}
+
+// redeem checks that the token confirms the call identified by key. A token
+// can only be used once.
+func (s *confirmStore) redeem(token, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p, ok := s.pending[token]
+	if !ok {
+		return errors.New("unknown or already used confirm_token, call again without it to get a new one")
+	}
+	if s.now().After(p.expires) {
+		delete(s.pending, token)
+		return errors.New("confirm_token expired, call again without it to get a new one")
+	}
+	if p.key != key {
+		return errors.New("confirm_token was issued for a call with different arguments")
+	}
+	delete(s.pending, token)
+	return nil
+}
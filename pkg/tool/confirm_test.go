@@ -0,0 +1,248 @@
+package tool
+
+import (
+	"context"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestWithServerDryRun(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{
+			args: []string{"delete", "pod", "web"},
+			want: []string{"delete", "pod", "web", "--dry-run=server"},
+		},
+		{
+			args: []string{"run", "debug", "--image", "busybox", "--", "sleep", "10"},
+			want: []string{"run", "debug", "--image", "busybox", "--dry-run=server", "--", "sleep", "10"},
+		},
+	}
+	for _, tt := range tests {
+		if got := withServerDryRun(tt.args); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("withServerDryRun(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestDestructiveAction(t *testing.T) {
+	tests := []struct {
+		name        string
+		tool        string
+		args        map[string]interface{}
+		wantReason  string
+		wantCommand string
+	}{
+		{
+			name:        "delete all",
+			tool:        "kubectl_delete",
+			args:        map[string]interface{}{"resource": "pods", "namespace": "shop", "all": true},
+			wantReason:  "deletes all pods in the namespace",
+			wantCommand: "kubectl delete pods -n shop --all",
+		},
+		{
+			name: "apply without force",
+			tool: "kubectl_apply",
+			args: map[string]interface{}{"filename": "app.yaml"},
+		},
+		{
+			name:        "apply with force",
+			tool:        "kubectl_apply",
+			args:        map[string]interface{}{"filename": "app.yaml", "force": true, "validate": true},
+			wantReason:  "applies with --force, which deletes and re-creates resources that fail to update",
+			wantCommand: "kubectl apply -f app.yaml --force",
+		},
+		{
+			name:        "generic scale to zero",
+			tool:        "kubectl_generic",
+			args:        map[string]interface{}{"args": "scale deployment/web --replicas=0"},
+			wantReason:  "scales to zero replicas",
+			wantCommand: "kubectl scale deployment/web --replicas=0",
+		},
+		{
+			name:        "generic drain",
+			tool:        "kubectl_generic",
+			args:        map[string]interface{}{"args": "drain node-1 --ignore-daemonsets"},
+			wantReason:  "evicts all pods from the node",
+			wantCommand: "kubectl drain node-1 --ignore-daemonsets",
+		},
+		{
+			name:        "generic write verb",
+			tool:        "kubectl_generic",
+			args:        map[string]interface{}{"args": []interface{}{"label", "pod", "web", "tier=frontend"}},
+			wantReason:  "runs kubectl label, which changes the cluster",
+			wantCommand: "kubectl label pod web tier=frontend",
+		},
+		{
+			name:        "generic rollout restart",
+			tool:        "kubectl_generic",
+			args:        map[string]interface{}{"args": "rollout restart deployment/web"},
+			wantReason:  "runs kubectl rollout, which changes the cluster",
+			wantCommand: "kubectl rollout restart deployment/web",
+		},
+		{
+			name: "generic rollout status",
+			tool: "kubectl_generic",
+			args: map[string]interface{}{"args": "rollout status deployment/web"},
+		},
+		{
+			name: "generic read",
+			tool: "kubectl_generic",
+			args: map[string]interface{}{"args": "get pods -A"},
+		},
+		{
+			name: "generic dry-run",
+			tool: "kubectl_generic",
+			args: map[string]interface{}{"args": "delete pod web --dry-run=server"},
+		},
+		{
+			name:        "apply manifest with force conflicts",
+			tool:        "apply_manifest",
+			args:        map[string]interface{}{"manifest": "kind: ConfigMap", "force_conflicts": true},
+			wantReason:  "applies with force_conflicts, taking ownership of fields managed by others",
+			wantCommand: `server-side apply of the manifest with force_conflicts as field manager "kube-mcp-server"`,
+		},
+		{
+			name: "not a destructive tool",
+			tool: "kubectl_get",
+			args: map[string]interface{}{"resource": "pods"},
+		},
+	}
+	h := &Handler{fieldManager: defaultFieldManager}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = tt.args
+			action := h.destructiveAction(tt.tool, req, nil)
+			if tt.wantReason == "" {
+				if action != nil {
+					t.Fatalf("destructiveAction() = %q, want nil", action.reason)
+				}
+				return
+			}
+			if action == nil {
+				t.Fatal("destructiveAction() = nil, want an action")
+			}
+			if action.reason != tt.wantReason || action.command != tt.wantCommand {
+				t.Errorf("destructiveAction() = %q, %q, want %q, %q", action.reason, action.command, tt.wantReason, tt.wantCommand)
+			}
+		})
+	}
+}
+
+func TestRequireConfirmationToken(t *testing.T) {
+	h := &Handler{
+		fieldManager:            defaultFieldManager,
+		destructiveConfirmation: true,
+		confirmations:           newConfirmStore(time.Minute),
+	}
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	h.confirmations.now = func() time.Time { return now }
+
+	var applied []map[string]interface{}
+	handler := h.requireConfirmation("apply_manifest", func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if dryRun, _ := req.GetArguments()["dry_run"].(bool); dryRun {
+			return mcp.NewToolResultText("configmap/app configured (dry run)"), nil
+		}
+		applied = append(applied, req.GetArguments())
+		return mcp.NewToolResultText("configmap/app configured"), nil
+	})
+	call := func(args map[string]interface{}) *mcp.CallToolResult {
+		t.Helper()
+		req := mcp.CallToolRequest{}
+		req.Params.Arguments = args
+		result, err := handler(context.Background(), req)
+		if err != nil {
+			t.Fatalf("handler returned error: %v", err)
+		}
+		return result
+	}
+	args := func(extra ...string) map[string]interface{} {
+		a := map[string]interface{}{"manifest": "kind: ConfigMap", "force_conflicts": true}
+		for i := 0; i+1 < len(extra); i += 2 {
+			a[extra[i]] = extra[i+1]
+		}
+		return a
+	}
+	tokenPattern := regexp.MustCompile(`confirm_token "([0-9a-f]+)"`)
+	newToken := func() string {
+		t.Helper()
+		result := call(args())
+		text := resultText(result)
+		if !result.IsError || !strings.Contains(text, "configmap/app configured (dry run)") {
+			t.Fatalf("first call = %q, want a confirmation request with the dry-run preview", text)
+		}
+		match := tokenPattern.FindStringSubmatch(text)
+		if match == nil {
+			t.Fatalf("no confirm_token in %q", text)
+		}
+		return match[1]
+	}
+
+	token := newToken()
+	if len(applied) != 0 {
+		t.Fatal("handler ran before confirmation")
+	}
+
+	if result := call(args("confirm_token", token, "namespace", "other")); !result.IsError {
+		t.Error("token accepted for different arguments")
+	}
+	if result := call(args("confirm_token", token, "timeout", "2m")); result.IsError {
+		t.Fatalf("confirmed call with a different timeout failed: %s", resultText(result))
+	}
+	if len(applied) != 1 {
+		t.Fatalf("handler ran %d times, want 1", len(applied))
+	}
+	if _, ok := applied[0]["confirm_token"]; ok {
+		t.Error("confirm_token was passed on to the handler")
+	}
+	if result := call(args("confirm_token", token)); !result.IsError {
+		t.Error("token accepted twice")
+	}
+
+	token = newToken()
+	now = now.Add(2 * time.Minute)
+	if result := call(args("confirm_token", token)); !result.IsError || !strings.Contains(resultText(result), "expired") {
+		t.Errorf("expired token result = %q, want an expiry error", resultText(result))
+	}
+	if len(applied) != 1 {
+		t.Errorf("handler ran %d times, want 1", len(applied))
+	}
+
+	if result := call(map[string]interface{}{"manifest": "kind: ConfigMap", "dry_run": true}); result.IsError {
+		t.Errorf("dry-run call required confirmation: %s", resultText(result))
+	}
+}
+
+func TestConfirmPreviewDeadline(t *testing.T) {
+	h := &Handler{
+		fieldManager:            defaultFieldManager,
+		destructiveConfirmation: true,
+		confirmations:           newConfirmStore(time.Minute),
+		toolTimeout:             time.Minute,
+		maxToolTimeout:          time.Minute,
+	}
+	var previewTimeout time.Duration
+	handler := h.requireConfirmation("apply_manifest", func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if deadline, ok := ctx.Deadline(); ok {
+			previewTimeout = time.Until(deadline)
+		}
+		return mcp.NewToolResultText("configmap/app configured (dry run)"), nil
+	})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"manifest": "kind: ConfigMap", "force_conflicts": true, "timeout": "10s"}
+	if result, err := handler(context.Background(), req); err != nil || !result.IsError {
+		t.Fatalf("handler() = %v, %v, want a confirmation request", result, err)
+	}
+	if previewTimeout <= 0 || previewTimeout > 10*time.Second {
+		t.Errorf("preview ran with timeout %s, want at most the call's 10s", previewTimeout)
+	}
+}
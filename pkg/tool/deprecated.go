@@ -0,0 +1,347 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// deprecatedAPI describes an API version of a kind that is deprecated and
+// removed in a given Kubernetes minor release.
+type deprecatedAPI struct {
+	GroupVersion string
+	Kind         string
+	DeprecatedIn string
+	RemovedIn    string
+	Replacement  string
+}
+
+// deprecatedAPIs is the built-in table of deprecated and removed APIs, based
+// on the Kubernetes deprecated API migration guide.
+var deprecatedAPIs = []deprecatedAPI{
+	// v1.16
+	{"extensions/v1beta1", "Deployment", "1.9", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "DaemonSet", "1.9", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "ReplicaSet", "1.9", "1.16", "apps/v1"},
+	{"extensions/v1beta1", "NetworkPolicy", "1.9", "1.16", "networking.k8s.io/v1"},
+	{"extensions/v1beta1", "PodSecurityPolicy", "1.11", "1.16", "policy/v1beta1"},
+	{"apps/v1beta1", "Deployment", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta1", "StatefulSet", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "Deployment", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "StatefulSet", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "DaemonSet", "1.9", "1.16", "apps/v1"},
+	{"apps/v1beta2", "ReplicaSet", "1.9", "1.16", "apps/v1"},
+
+	// v1.22
+	{"extensions/v1beta1", "Ingress", "1.14", "1.22", "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", "Ingress", "1.19", "1.22", "networking.k8s.io/v1"},
+	{"networking.k8s.io/v1beta1", "IngressClass", "1.19", "1.22", "networking.k8s.io/v1"},
+	{"apiextensions.k8s.io/v1beta1", "CustomResourceDefinition", "1.16", "1.22", "apiextensions.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", "MutatingWebhookConfiguration", "1.16", "1.22", "admissionregistration.k8s.io/v1"},
+	{"admissionregistration.k8s.io/v1beta1", "ValidatingWebhookConfiguration", "1.16", "1.22", "admissionregistration.k8s.io/v1"},
+	{"apiregistration.k8s.io/v1beta1", "APIService", "1.19", "1.22", "apiregistration.k8s.io/v1"},
+	{"certificates.k8s.io/v1beta1", "CertificateSigningRequest", "1.19", "1.22", "certificates.k8s.io/v1"},
+	{"coordination.k8s.io/v1beta1", "Lease", "1.19", "1.22", "coordination.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRole", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "ClusterRoleBinding", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "Role", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"rbac.authorization.k8s.io/v1beta1", "RoleBinding", "1.17", "1.22", "rbac.authorization.k8s.io/v1"},
+	{"scheduling.k8s.io/v1beta1", "PriorityClass", "1.14", "1.22", "scheduling.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSIDriver", "1.19", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "CSINode", "1.17", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "StorageClass", "1.19", "1.22", "storage.k8s.io/v1"},
+	{"storage.k8s.io/v1beta1", "VolumeAttachment", "1.19", "1.22", "storage.k8s.io/v1"},
+
+	// v1.25
+	{"batch/v1beta1", "CronJob", "1.21", "1.25", "batch/v1"},
+	{"discovery.k8s.io/v1beta1", "EndpointSlice", "1.21", "1.25", "discovery.k8s.io/v1"},
+	{"events.k8s.io/v1beta1", "Event", "1.22", "1.25", "events.k8s.io/v1"},
+	{"autoscaling/v2beta1", "HorizontalPodAutoscaler", "1.22", "1.25", "autoscaling/v2"},
+	{"policy/v1beta1", "PodDisruptionBudget", "1.21", "1.25", "policy/v1"},
+	{"policy/v1beta1", "PodSecurityPolicy", "1.21", "1.25", ""},
+	{"node.k8s.io/v1beta1", "RuntimeClass", "1.22", "1.25", "node.k8s.io/v1"},
+
+	// v1.26
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "FlowSchema", "1.23", "1.26", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta1", "PriorityLevelConfiguration", "1.23", "1.26", "flowcontrol.apiserver.k8s.io/v1"},
+	{"autoscaling/v2beta2", "HorizontalPodAutoscaler", "1.23", "1.26", "autoscaling/v2"},
+
+	// v1.27
+	{"storage.k8s.io/v1beta1", "CSIStorageCapacity", "1.24", "1.27", "storage.k8s.io/v1"},
+
+	// v1.29
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "FlowSchema", "1.26", "1.29", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta2", "PriorityLevelConfiguration", "1.26", "1.29", "flowcontrol.apiserver.k8s.io/v1"},
+
+	// v1.32
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "FlowSchema", "1.29", "1.32", "flowcontrol.apiserver.k8s.io/v1"},
+	{"flowcontrol.apiserver.k8s.io/v1beta3", "PriorityLevelConfiguration", "1.29", "1.32", "flowcontrol.apiserver.k8s.io/v1"},
+}
+
+// findDeprecatedAPI returns the table entry for the apiVersion and kind, if any.
+func findDeprecatedAPI(apiVersion, kind string) (deprecatedAPI, bool) {
+	for _, api := range deprecatedAPIs {
+		if api.GroupVersion == apiVersion && api.Kind == kind {
+			return api, true
+		}
+	}
+	return deprecatedAPI{}, false
+}
+
+// parseMinorVersion parses versions like "1.25", "v1.25" or "v1.25.3-gke.1"
+// into their minor number. Only Kubernetes 1.x is supported.
+func parseMinorVersion(version string) (int, error) {
+	v := strings.TrimPrefix(strings.TrimSpace(version), "v")
+	major, rest, ok := strings.Cut(v, ".")
+	if !ok || major != "1" {
+		return 0, fmt.Errorf("invalid Kubernetes version %q (expected e.g. 1.29)", version)
+	}
+	minor := rest
+	if i := strings.IndexFunc(rest, func(r rune) bool { return r < '0' || r > '9' }); i >= 0 {
+		minor = rest[:i]
+	}
+	n, err := strconv.Atoi(minor)
+	if err != nil {
+		return 0, fmt.Errorf("invalid Kubernetes version %q (expected e.g. 1.29)", version)
+	}
+	return n, nil
+}
+
+// removedBy reports whether the API is removed in or before the target minor version.
+func (d deprecatedAPI) removedBy(targetMinor int) bool {
+	removed, err := parseMinorVersion(d.RemovedIn)
+	return err == nil && removed <= targetMinor
+}
+
+type deprecatedAPIFinding struct {
+	Object       string `json:"object"`
+	Source       string `json:"source"`
+	APIVersion   string `json:"apiVersion"`
+	Kind         string `json:"kind"`
+	DeprecatedIn string `json:"deprecatedIn"`
+	RemovedIn    string `json:"removedIn"`
+	Replacement  string `json:"replacement"`
+}
+
+type deprecatedAPIsResult struct {
+	ServerVersion string                 `json:"serverVersion"`
+	TargetVersion string                 `json:"targetVersion"`
+	ServedRemoved []string               `json:"servedRemoved,omitempty"`
+	TotalFindings int                    `json:"totalFindings"`
+	Findings      []deprecatedAPIFinding `json:"findings"`
+	Warnings      []string               `json:"warnings,omitempty"`
+}
+
+func (h *Handler) registerDeprecated(m *server.MCPServer) {
+	h.addTool(m, mcp.NewTool("deprecated_apis",
+		mcp.WithDescription("Find objects authored against API versions that are removed by a target Kubernetes version, by scanning last-applied-configuration annotations, managedFields API versions and Helm release manifests, and report the replacement API"),
+		mcp.WithOutputSchema[deprecatedAPIsResult](),
+		mcp.WithString("target_version",
+			mcp.Description("Kubernetes version to upgrade to (e.g., '1.29' or 'v1.32')"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Only scan this namespace (cluster-scoped objects are always scanned); leave empty for all namespaces"),
+		),
+		mcp.WithBoolean("include_helm",
+			mcp.Description("Also scan the manifests of deployed Helm releases"),
+			mcp.DefaultBool(true),
+		),
+	), mcp.NewTypedToolHandler[DeprecatedAPIsArgs](h.deprecatedAPIsHandler()))
+}
+
+type DeprecatedAPIsArgs struct {
+	TargetVersion string `json:"targetVersion"`
+	Namespace     string `json:"namespace,omitempty"`
+	IncludeHelm   *bool  `json:"include_helm,omitempty"`
+}
+
+func (h *Handler) deprecatedAPIsHandler() mcp.TypedToolHandlerFunc[DeprecatedAPIsArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args DeprecatedAPIsArgs,
+	) (*mcp.CallToolResult, error) {
+		targetMinor, err := parseMinorVersion(args.TargetVersion)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		serverVersion := "unknown"
+		if info, err := h.client.Discovery().ServerVersion(); err == nil {
+			serverVersion = info.GitVersion
+		}
+
+		findings := []deprecatedAPIFinding{}
+		add := func(object, source, apiVersion, kind string) {
+			api, ok := findDeprecatedAPI(apiVersion, kind)
+			if !ok || !api.removedBy(targetMinor) {
+				return
+			}
+			findings = append(findings, deprecatedAPIFinding{
+				Object: object, Source: source, APIVersion: apiVersion, Kind: kind,
+				DeprecatedIn: api.DeprecatedIn, RemovedIn: api.RemovedIn, Replacement: api.Replacement,
+			})
+		}
+
+		var warnings []string
+		if err := h.scanObjectAPIVersions(ctx, args.Namespace, add); err != nil {
+			warnings = append(warnings, err.Error())
+		}
+		if args.IncludeHelm == nil || *args.IncludeHelm {
+			if err := h.scanHelmAPIVersions(ctx, args.Namespace, add); err != nil {
+				warnings = append(warnings, err.Error())
+			}
+		}
+
+		sort.SliceStable(findings, func(i, j int) bool { return findings[i].Object < findings[j].Object })
+
+		result, err := structuredResult(deprecatedAPIsResult{
+			ServerVersion: serverVersion,
+			TargetVersion: fmt.Sprintf("1.%d", targetMinor),
+			ServedRemoved: h.servedRemovedAPIs(targetMinor),
+			TotalFindings: len(findings),
+			Findings:      findings,
+			Warnings:      warnings,
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to marshal findings", err), nil
+		}
+		return result, nil
+	}
+}
+
+// servedRemovedAPIs returns the API versions from the table the server still
+// serves even though the target version removes them.
+func (h *Handler) servedRemovedAPIs(targetMinor int) []string {
+	_, lists, err := h.discovery.ServerGroupsAndResources()
+	if err != nil && len(lists) == 0 {
+		return nil
+	}
+
+	served := map[string]bool{}
+	for _, list := range lists {
+		for _, r := range list.APIResources {
+			served[list.GroupVersion+"/"+r.Kind] = true
+		}
+	}
+
+	var result []string
+	for _, api := range deprecatedAPIs {
+		if api.removedBy(targetMinor) && served[api.GroupVersion+"/"+api.Kind] {
+			result = append(result, fmt.Sprintf("%s %s (removed in %s)", api.GroupVersion, api.Kind, api.RemovedIn))
+		}
+	}
+	return result
+}
+
+// scanObjectAPIVersions lists the objects of every kind in the table through
+// their currently served version, and reports the API versions recorded in the
+// last-applied annotation and in managedFields.
+func (h *Handler) scanObjectAPIVersions(ctx context.Context, namespace string, add func(object, source, apiVersion, kind string)) error {
+	seen := map[schema.GroupResource]bool{}
+	var failures []string
+	for _, api := range deprecatedAPIs {
+		replacement := api.Replacement
+		if replacement == "" {
+			replacement = api.GroupVersion
+		}
+		gv, err := schema.ParseGroupVersion(replacement)
+		if err != nil {
+			continue
+		}
+		mapping, err := h.mapper.RESTMapping(schema.GroupKind{Group: gv.Group, Kind: api.Kind})
+		if err != nil || seen[mapping.Resource.GroupResource()] {
+			continue
+		}
+		seen[mapping.Resource.GroupResource()] = true
+
+		ns := namespace
+		if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+			ns = ""
+		}
+		list, err := h.metadata.Resource(mapping.Resource).Namespace(ns).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			log.Debug().Err(err).Str("resource", mapping.Resource.String()).Msg("Skipping resource in deprecated API scan")
+			failures = append(failures, mapping.Resource.GroupResource().String())
+			continue
+		}
+
+		for _, item := range list.Items {
+			ref := api.Kind + " " + item.Name
+			if item.Namespace != "" {
+				ref = fmt.Sprintf("%s %s/%s", api.Kind, item.Namespace, item.Name)
+			}
+
+			if lastApplied, ok := item.Annotations[lastAppliedAnnotation]; ok {
+				var typeMeta metav1.TypeMeta
+				if err := json.Unmarshal([]byte(lastApplied), &typeMeta); err == nil {
+					add(ref, "last-applied-configuration", typeMeta.APIVersion, typeMeta.Kind)
+				}
+			}
+			reported := map[string]bool{}
+			for _, mf := range item.ManagedFields {
+				if reported[mf.APIVersion+mf.Manager] {
+					continue
+				}
+				reported[mf.APIVersion+mf.Manager] = true
+				add(ref, fmt.Sprintf("managedFields (manager %s)", mf.Manager), mf.APIVersion, api.Kind)
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("could not list %s", strings.Join(failures, ", "))
+	}
+	return nil
+}
+
+// scanHelmAPIVersions reports deprecated API versions in the manifests of the
+// latest revision of every Helm release.
+func (h *Handler) scanHelmAPIVersions(ctx context.Context, namespace string, add func(object, source, apiVersion, kind string)) error {
+	releases, err := h.listHelmReleases(ctx, namespace, "")
+	if err != nil {
+		return err
+	}
+
+	return scanHelmManifests(latestHelmReleases(releases), add)
+}
+
+// scanHelmManifests reports the API versions of the objects in the manifests
+// of the releases. Releases whose manifest can't be decoded are returned in
+// the error, as they were not checked.
+func scanHelmManifests(releases []*helmRelease, add func(object, source, apiVersion, kind string)) error {
+	var failures []string
+	for _, release := range releases {
+		objects, err := decodeManifest(release.Manifest)
+		if err != nil {
+			log.Debug().Err(err).Str("release", release.String()).Msg("Skipping Helm release in deprecated API scan")
+			failures = append(failures, release.String())
+			continue
+		}
+		for _, obj := range objects {
+			ref := fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName())
+			if ns := obj.GetNamespace(); ns != "" {
+				ref = fmt.Sprintf("%s %s/%s", obj.GetKind(), ns, obj.GetName())
+			}
+			add(ref, "helm release "+release.String(), obj.GetAPIVersion(), obj.GetKind())
+		}
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("Helm releases not checked, their manifest could not be decoded: %s", strings.Join(failures, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package tool
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseMinorVersion(t *testing.T) {
+	tests := []struct {
+		version string
+		want    int
+		wantErr bool
+	}{
+		{"1.29", 29, false},
+		{"v1.32", 32, false},
+		{"v1.25.3-gke.1", 25, false},
+		{" 1.16 ", 16, false},
+		{"2.0", 0, true},
+		{"latest", 0, true},
+		{"1.", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseMinorVersion(tt.version)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseMinorVersion(%q) error = %v, wantErr %v", tt.version, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseMinorVersion(%q) = %d, want %d", tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestDeprecatedAPIRemovedBy(t *testing.T) {
+	tests := []struct {
+		apiVersion string
+		kind       string
+		target     int
+		want       bool
+	}{
+		{"batch/v1beta1", "CronJob", 24, false},
+		{"batch/v1beta1", "CronJob", 25, true},
+		{"autoscaling/v2beta2", "HorizontalPodAutoscaler", 29, true},
+		{"flowcontrol.apiserver.k8s.io/v1beta3", "FlowSchema", 31, false},
+		{"apps/v1", "Deployment", 32, false},
+	}
+	for _, tt := range tests {
+		api, ok := findDeprecatedAPI(tt.apiVersion, tt.kind)
+		if got := ok && api.removedBy(tt.target); got != tt.want {
+			t.Errorf("%s %s removed by 1.%d = %v, want %v", tt.apiVersion, tt.kind, tt.target, got, tt.want)
+		}
+	}
+
+	for _, api := range deprecatedAPIs {
+		if _, err := parseMinorVersion(api.RemovedIn); err != nil {
+			t.Errorf("%s %s has invalid removal version: %v", api.GroupVersion, api.Kind, err)
+		}
+	}
+}
+
+func TestScanHelmManifests(t *testing.T) {
+	releases := []*helmRelease{
+		{Name: "web", Namespace: "shop", Version: 3, Manifest: "apiVersion: policy/v1beta1\nkind: PodDisruptionBudget\nmetadata:\n  name: web\n  namespace: shop\n"},
+		{Name: "broken", Namespace: "shop", Version: 1, Manifest: "apiVersion: v1\nkind: [ConfigMap\n"},
+	}
+
+	var found []string
+	err := scanHelmManifests(releases, func(object, source, apiVersion, kind string) {
+		found = append(found, fmt.Sprintf("%s %s %s", object, source, apiVersion))
+	})
+	want := []string{"PodDisruptionBudget shop/web helm release shop/web (revision 3) policy/v1beta1"}
+	if !reflect.DeepEqual(found, want) {
+		t.Errorf("scanHelmManifests() found %v, want %v", found, want)
+	}
+	if err == nil || !strings.Contains(err.Error(), "shop/broken (revision 1)") {
+		t.Errorf("scanHelmManifests() error = %v, want the undecodable release named", err)
+	}
+}
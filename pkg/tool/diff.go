@@ -0,0 +1,258 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/pmezard/go-difflib/difflib"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+func (h *Handler) registerDiff(m *server.MCPServer) {
+	h.addTool(m, mcp.NewTool("diff_manifest",
+		mcp.WithDescription("Show what applying inline YAML or JSON manifests would change, using a server-side dry-run apply compared against the live objects, with Secret data redacted"),
+		mcp.WithString("manifest",
+			mcp.Description("YAML or JSON content of the objects to diff (multiple YAML documents and kind: List are supported)"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace for namespaced objects that don't set metadata.namespace (defaults to the server's default namespace)"),
+		),
+		mcp.WithString("field_manager",
+			mcp.Description("Field manager name to dry-run the apply as (defaults to the server's configured field manager)"),
+		),
+		mcp.WithBoolean("force_conflicts",
+			mcp.Description("Diff as if conflicting fields managed by other field managers were taken over"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithOutputSchema[diffManifestResult](),
+	), mcp.NewTypedToolHandler[DiffManifestArgs](h.diffManifestHandler()))
+}
+
+type DiffManifestArgs struct {
+	Manifest       string `json:"manifest"`
+	Namespace      string `json:"namespace,omitempty"`
+	FieldManager   string `json:"field_manager,omitempty"`
+	ForceConflicts bool   `json:"force_conflicts"`
+}
+
+type diffResult struct {
+	Ref     string `json:"ref"`
+	Status  string `json:"status"`
+	Diff    string `json:"diff,omitempty"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	Error   string `json:"error,omitempty"`
+}
+
+// diffManifestResult is the structured output of diff_manifest, with the
+// number of objects per status.
+type diffManifestResult struct {
+	FieldManager string       `json:"fieldManager"`
+	Added        int          `json:"added"`
+	Changed      int          `json:"changed"`
+	Unchanged    int          `json:"unchanged"`
+	Failed       int          `json:"failed"`
+	Objects      []diffResult `json:"objects"`
+}
+
+func newDiffManifestResult(fieldManager string, results []diffResult) diffManifestResult {
+	summary := diffManifestResult{FieldManager: fieldManager, Objects: results}
+	if summary.Objects == nil {
+		summary.Objects = []diffResult{}
+	}
+	for _, r := range results {
+		switch r.Status {
+		case "added":
+			summary.Added++
+		case "changed":
+			summary.Changed++
+		case "unchanged":
+			summary.Unchanged++
+		case "failed":
+			summary.Failed++
+		}
+	}
+	return summary
+}
+
+func (h *Handler) diffManifestHandler() mcp.TypedToolHandlerFunc[DiffManifestArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args DiffManifestArgs,
+	) (*mcp.CallToolResult, error) {
+		objects, err := decodeManifest(args.Manifest)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid manifest", err), nil
+		}
+
+		fieldManager := args.FieldManager
+		if fieldManager == "" {
+			fieldManager = h.fieldManager
+		}
+		opts := metav1.ApplyOptions{
+			FieldManager: fieldManager,
+			Force:        args.ForceConflicts,
+			DryRun:       []string{metav1.DryRunAll},
+		}
+
+		var results []diffResult
+		for _, obj := range objects {
+			results = append(results, h.diffObject(ctx, obj, args.Namespace, opts))
+		}
+
+		structured := newDiffManifestResult(fieldManager, results)
+		result := mcp.NewToolResultStructured(structured, formatDiff(structured))
+		result.IsError = structured.Failed > 0
+		return result, nil
+	}
+}
+
+// formatDiff renders the diff results with a summary of the counts per status.
+func formatDiff(d diffManifestResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Diff (server-side dry-run, field manager: %s): %d added, %d changed, %d unchanged, %d failed\n",
+		d.FieldManager, d.Added, d.Changed, d.Unchanged, d.Failed))
+	for _, r := range d.Objects {
+		sb.WriteString("\n")
+		switch r.Status {
+		case "failed":
+			sb.WriteString(fmt.Sprintf("%s: failed: %s\n", r.Ref, r.Error))
+		case "unchanged":
+			sb.WriteString(fmt.Sprintf("%s: unchanged\n", r.Ref))
+		default:
+			sb.WriteString(fmt.Sprintf("%s: %s (+%d -%d)\n", r.Ref, r.Status, r.Added, r.Removed))
+			sb.WriteString(r.Diff)
+		}
+	}
+	return sb.String()
+}
+
+// diffObject performs a server-side dry-run apply of the object and diffs the
+// result against the live object. Objects that don't exist yet are diffed
+// against an empty document.
+func (h *Handler) diffObject(ctx context.Context, obj *unstructured.Unstructured, namespace string, opts metav1.ApplyOptions) diffResult {
+	ri, mapping, err := h.resourceInterfaceFor(obj, namespace)
+	if err != nil {
+		return diffResult{Ref: fmt.Sprintf("%s/%s", obj.GetKind(), obj.GetName()), Status: "failed", Error: err.Error()}
+	}
+	ref := objectRef(mapping, obj)
+
+	live, err := ri.Get(ctx, obj.GetName(), metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		live = nil
+	case err != nil:
+		return diffResult{Ref: ref, Status: "failed", Error: fmt.Sprintf("failed to get live object: %v", err)}
+	}
+
+	applied, err := ri.Apply(ctx, obj.GetName(), obj, opts)
+	if err != nil {
+		msg := err.Error()
+		if conflicts := fieldConflicts(err); len(conflicts) > 0 {
+			msg += " (set force_conflicts to take ownership)"
+		}
+		return diffResult{Ref: ref, Status: "failed", Error: msg}
+	}
+	return diffObjects(ref, live, applied)
+}
+
+// diffObjects diffs the live object against the dry-run result, with status,
+// managed fields and Secret data left out. A nil live object is diffed as an
+// empty document.
+func diffObjects(ref string, live, applied *unstructured.Unstructured) diffResult {
+	status := "added"
+	var liveObj map[string]interface{}
+	if live != nil {
+		status = "changed"
+		liveObj = normalizeObject(live)
+	}
+	appliedObj := normalizeObject(applied)
+	redactSecretData(liveObj, appliedObj)
+
+	var liveYAML []byte
+	if liveObj != nil {
+		var err error
+		if liveYAML, err = yaml.Marshal(liveObj); err != nil {
+			return diffResult{Ref: ref, Status: "failed", Error: fmt.Sprintf("failed to marshal live object: %v", err)}
+		}
+	}
+	appliedYAML, err := yaml.Marshal(appliedObj)
+	if err != nil {
+		return diffResult{Ref: ref, Status: "failed", Error: fmt.Sprintf("failed to marshal dry-run result: %v", err)}
+	}
+
+	diff, added, removed, err := unifiedDiff(string(liveYAML), string(appliedYAML), "live/"+ref, "merged/"+ref)
+	if err != nil {
+		return diffResult{Ref: ref, Status: "failed", Error: err.Error()}
+	}
+	if diff == "" {
+		status = "unchanged"
+	}
+
+	return diffResult{Ref: ref, Status: status, Diff: diff, Added: added, Removed: removed}
+}
+
+// redactSecretData replaces the data of Secrets in place, like kubectl diff:
+// values that differ between the live and applied object are marked as the
+// before and after value so the diff still shows which keys changed.
+func redactSecretData(live, applied map[string]interface{}) {
+	if applied["kind"] != "Secret" {
+		return
+	}
+	for _, field := range []string{"data", "stringData"} {
+		liveData, _ := live[field].(map[string]interface{})
+		appliedData, _ := applied[field].(map[string]interface{})
+		for k, before := range liveData {
+			after, ok := appliedData[k]
+			if ok && !reflect.DeepEqual(before, after) {
+				liveData[k], appliedData[k] = redacted+" (before)", redacted+" (after)"
+				continue
+			}
+			liveData[k] = redacted
+			if ok {
+				appliedData[k] = redacted
+			}
+		}
+		for k := range appliedData {
+			if _, ok := liveData[k]; !ok {
+				appliedData[k] = redacted
+			}
+		}
+	}
+}
+
+// unifiedDiff returns a unified diff between two texts along with the number
+// of added and removed lines.
+func unifiedDiff(a, b, fromFile, toFile string) (string, int, int, error) {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(a),
+		B:        difflib.SplitLines(b),
+		FromFile: fromFile,
+		ToFile:   toFile,
+		Context:  3,
+	})
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("failed to compute diff: %w", err)
+	}
+
+	added, removed := 0, 0
+	for _, line := range strings.Split(diff, "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+		case strings.HasPrefix(line, "+"):
+			added++
+		case strings.HasPrefix(line, "-"):
+			removed++
+		}
+	}
+	return diff, added, removed, nil
+}
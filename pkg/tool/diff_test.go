@@ -0,0 +1,153 @@
+package tool
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestDiffObjects(t *testing.T) {
+	configMap := func(data map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name":            "app",
+				"namespace":       "default",
+				"resourceVersion": "42",
+				"managedFields":   []interface{}{map[string]interface{}{"manager": "kubectl"}},
+			},
+			"data": data,
+		}}
+	}
+	secret := func(data map[string]interface{}) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   map[string]interface{}{"name": "creds", "namespace": "default"},
+			"data":       data,
+		}}
+	}
+	bumped := func(obj *unstructured.Unstructured) *unstructured.Unstructured {
+		obj.SetResourceVersion("43")
+		obj.SetGeneration(2)
+		return obj
+	}
+
+	tests := []struct {
+		name        string
+		live        *unstructured.Unstructured
+		applied     *unstructured.Unstructured
+		wantStatus  string
+		wantAdded   int
+		wantRemoved int
+		wantLines   []string
+		notWant     []string
+	}{
+		{
+			name:       "added",
+			applied:    configMap(map[string]interface{}{"mode": "fast"}),
+			wantStatus: "added",
+			wantAdded:  7,
+			wantLines:  []string{"--- live/configmap/app", "+++ merged/configmap/app", "+  mode: fast", "+kind: ConfigMap"},
+			notWant:    []string{"resourceVersion", "managedFields"},
+		},
+		{
+			name:        "changed",
+			live:        configMap(map[string]interface{}{"mode": "fast", "level": "debug"}),
+			applied:     configMap(map[string]interface{}{"mode": "slow", "level": "debug"}),
+			wantStatus:  "changed",
+			wantAdded:   1,
+			wantRemoved: 1,
+			wantLines:   []string{"-  mode: fast", "+  mode: slow", "   level: debug"},
+		},
+		{
+			name:       "unchanged ignores server-managed fields",
+			live:       configMap(map[string]interface{}{"mode": "fast"}),
+			applied:    bumped(configMap(map[string]interface{}{"mode": "fast"})),
+			wantStatus: "unchanged",
+		},
+		{
+			name:        "secret values are redacted",
+			live:        secret(map[string]interface{}{"password": "b2xk", "user": "YWRtaW4="}),
+			applied:     secret(map[string]interface{}{"password": "bmV3", "user": "YWRtaW4=", "token": "dG9rZW4="}),
+			wantStatus:  "changed",
+			wantAdded:   2,
+			wantRemoved: 1,
+			wantLines:   []string{"-  password: REDACTED (before)", "+  password: REDACTED (after)", "+  token: REDACTED", "   user: REDACTED"},
+			notWant:     []string{"b2xk", "bmV3", "YWRtaW4=", "dG9rZW4="},
+		},
+		{
+			name:       "unchanged secret",
+			live:       secret(map[string]interface{}{"password": "b2xk"}),
+			applied:    secret(map[string]interface{}{"password": "b2xk"}),
+			wantStatus: "unchanged",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := diffObjects("configmap/app", tt.live, tt.applied)
+			if got.Status != tt.wantStatus || got.Added != tt.wantAdded || got.Removed != tt.wantRemoved {
+				t.Fatalf("diffObjects() = %s (+%d -%d), want %s (+%d -%d)\n%s",
+					got.Status, got.Added, got.Removed, tt.wantStatus, tt.wantAdded, tt.wantRemoved, got.Diff)
+			}
+			if tt.wantStatus == "unchanged" && got.Diff != "" {
+				t.Errorf("unchanged diff = %q, want empty", got.Diff)
+			}
+			lines := strings.Split(got.Diff, "\n")
+			for _, want := range tt.wantLines {
+				if !containsString(lines, want) {
+					t.Errorf("diff has no line %q:\n%s", want, got.Diff)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got.Diff, notWant) {
+					t.Errorf("diff contains %q:\n%s", notWant, got.Diff)
+				}
+			}
+		})
+	}
+}
+
+func TestDiffObjectsDoesNotModifyInputs(t *testing.T) {
+	live := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1", "kind": "Secret",
+		"metadata": map[string]interface{}{"name": "creds"},
+		"data":     map[string]interface{}{"password": "b2xk"},
+	}}
+	applied := live.DeepCopy()
+	diffObjects("secret/creds", live, applied)
+	if got, _, _ := unstructured.NestedString(applied.Object, "data", "password"); got != "b2xk" {
+		t.Errorf("applied password = %q, want it unchanged", got)
+	}
+}
+
+func TestFormatDiff(t *testing.T) {
+	results := []diffResult{
+		{Ref: "configmap/app", Status: "changed", Diff: "--- live/configmap/app\n+++ merged/configmap/app\n", Added: 1, Removed: 1},
+		{Ref: "service/web", Status: "unchanged"},
+		{Ref: "deployment/web", Status: "added", Diff: "+kind: Deployment\n", Added: 1},
+		{Ref: "Widget/w", Status: "failed", Error: "no matches for kind"},
+	}
+	want := `Diff (server-side dry-run, field manager: kube-mcp-server): 1 added, 1 changed, 1 unchanged, 1 failed
+
+configmap/app: changed (+1 -1)
+--- live/configmap/app
++++ merged/configmap/app
+
+service/web: unchanged
+
+deployment/web: added (+1 -0)
++kind: Deployment
+
+Widget/w: failed: no matches for kind
+`
+	summary := newDiffManifestResult(defaultFieldManager, results)
+	if summary.Added != 1 || summary.Changed != 1 || summary.Unchanged != 1 || summary.Failed != 1 {
+		t.Errorf("newDiffManifestResult() = %+v, want one object per status", summary)
+	}
+	if got := formatDiff(summary); got != want {
+		t.Errorf("formatDiff() =\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,441 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/kube-openapi/pkg/spec3"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+func (h *Handler) registerDiscovery(m *server.MCPServer) {
+	h.addTool(m, mcp.NewTool("api_resources",
+		mcp.WithDescription("List the API resources supported by the cluster with their group/version, kind, scope, short names and verbs"),
+		mcp.WithString("api_group",
+			mcp.Description("Only list resources in this API group (use 'core' for the legacy core group)"),
+		),
+		mcp.WithString("verb",
+			mcp.Description("Only list resources that support this verb (e.g., list, watch, patch)"),
+		),
+		mcp.WithString("scope",
+			mcp.Description("Only list resources with this scope"),
+			mcp.Enum("namespaced", "cluster"),
+		),
+		mcp.WithOutputSchema[apiResourcesResult](),
+	), mcp.NewTypedToolHandler[APIResourcesArgs](h.apiResourcesHandler()))
+
+	h.addTool(m, mcp.NewTool("explain",
+		mcp.WithDescription("Explain a resource or one of its fields using the cluster's OpenAPI v3 schema: type, description, required fields, enum values and sub-fields"),
+		mcp.WithString("path",
+			mcp.Description("Resource and dotted field path (e.g., 'deployment', 'deployment.spec.strategy.rollingUpdate', 'pods.spec.containers.resources')"),
+			mcp.Required(),
+		),
+		mcp.WithString("api_version",
+			mcp.Description("API group/version to explain the resource in (e.g., 'apps/v1', 'autoscaling/v2'); defaults to the preferred version"),
+		),
+		mcp.WithOutputSchema[explainResult](),
+	), mcp.NewTypedToolHandler[ExplainArgs](h.explainHandler()))
+}
+
+type APIResourcesArgs struct {
+	APIGroup string `json:"api_group,omitempty"`
+	Verb     string `json:"verb,omitempty"`
+	Scope    string `json:"scope,omitempty"`
+}
+
+// apiResourcesResult is the structured output of api_resources.
+type apiResourcesResult struct {
+	Resources []apiResource `json:"resources"`
+	Warning   string        `json:"warning,omitempty"`
+}
+
+type apiResource struct {
+	Name       string   `json:"name"`
+	ShortNames []string `json:"shortNames,omitempty"`
+	APIVersion string   `json:"apiVersion"`
+	Namespaced bool     `json:"namespaced"`
+	Kind       string   `json:"kind"`
+	Verbs      []string `json:"verbs"`
+}
+
+func (h *Handler) apiResourcesHandler() mcp.TypedToolHandlerFunc[APIResourcesArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args APIResourcesArgs,
+	) (*mcp.CallToolResult, error) {
+		_, lists, err := h.discovery.ServerGroupsAndResources()
+		if err != nil && !discovery.IsGroupDiscoveryFailedError(err) {
+			return mcp.NewToolResultErrorFromErr("failed to discover API resources", err), nil
+		}
+
+		group := args.APIGroup
+		if group == "core" {
+			group = ""
+		}
+
+		var sb strings.Builder
+		w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "NAME\tSHORTNAMES\tAPIVERSION\tNAMESPACED\tKIND\tVERBS")
+		structured := apiResourcesResult{Resources: []apiResource{}}
+		for _, list := range lists {
+			gv, parseErr := schema.ParseGroupVersion(list.GroupVersion)
+			if parseErr != nil {
+				continue
+			}
+			if args.APIGroup != "" && gv.Group != group {
+				continue
+			}
+			for _, r := range list.APIResources {
+				if strings.Contains(r.Name, "/") {
+					continue
+				}
+				if args.Scope == "namespaced" && !r.Namespaced || args.Scope == "cluster" && r.Namespaced {
+					continue
+				}
+				if args.Verb != "" && !containsString(r.Verbs, args.Verb) {
+					continue
+				}
+				_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%t\t%s\t%s\n",
+					r.Name, strings.Join(r.ShortNames, ","), list.GroupVersion, r.Namespaced, r.Kind, strings.Join(r.Verbs, ","))
+				structured.Resources = append(structured.Resources, apiResource{
+					Name:       r.Name,
+					ShortNames: r.ShortNames,
+					APIVersion: list.GroupVersion,
+					Namespaced: r.Namespaced,
+					Kind:       r.Kind,
+					Verbs:      r.Verbs,
+				})
+			}
+		}
+		_ = w.Flush()
+
+		response := fmt.Sprintf("Found %d API resources\n\n%s", len(structured.Resources), sb.String())
+		if err != nil {
+			structured.Warning = fmt.Sprintf("some API groups could not be discovered: %v", err)
+			response += "\nWarning: " + structured.Warning + "\n"
+		}
+		return mcp.NewToolResultStructured(structured, response), nil
+	}
+}
+
+type ExplainArgs struct {
+	Path       string `json:"path"`
+	APIVersion string `json:"api_version,omitempty"`
+}
+
+func (h *Handler) explainHandler() mcp.TypedToolHandlerFunc[ExplainArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args ExplainArgs,
+	) (*mcp.CallToolResult, error) {
+		segments := strings.Split(strings.Trim(args.Path, "."), ".")
+		if len(segments) == 0 || segments[0] == "" {
+			return mcp.NewToolResultError("path must start with a resource name"), nil
+		}
+
+		gvk, err := h.explainKind(segments[0], args.APIVersion)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to resolve resource", err), nil
+		}
+
+		doc, err := h.openAPIDocument(gvk.GroupVersion())
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to load OpenAPI schema", err), nil
+		}
+
+		root := findKindSchema(doc, gvk)
+		if root == nil {
+			return mcp.NewToolResultError(fmt.Sprintf("no OpenAPI schema found for %s", gvk.String())), nil
+		}
+
+		current := resolveSchema(doc, root)
+		for i, field := range segments[1:] {
+			current = fieldSchema(doc, current, field)
+			if current == nil {
+				return mcp.NewToolResultError(fmt.Sprintf("field %q does not exist in %s", field, strings.Join(segments[:i+1], "."))), nil
+			}
+		}
+
+		explained := explainSchema(doc, gvk, segments, current)
+		return mcp.NewToolResultStructured(explained, formatExplain(explained)), nil
+	}
+}
+
+// explainKind resolves the resource name to a GroupVersionKind, optionally in
+// a specific API version.
+func (h *Handler) explainKind(resource, apiVersion string) (schema.GroupVersionKind, error) {
+	mapping, err := h.resolveResource(resource)
+	if err != nil {
+		return schema.GroupVersionKind{}, err
+	}
+	if apiVersion == "" {
+		return mapping.GroupVersionKind, nil
+	}
+
+	gv, err := schema.ParseGroupVersion(apiVersion)
+	if err != nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("invalid api_version %q: %w", apiVersion, err)
+	}
+	mapping, err = h.mapper.RESTMapping(mapping.GroupVersionKind.GroupKind(), gv.Version)
+	if err != nil {
+		return schema.GroupVersionKind{}, fmt.Errorf("%s is not served in %s: %w", resource, apiVersion, err)
+	}
+	return mapping.GroupVersionKind, nil
+}
+
+// openAPIDocument fetches the OpenAPI v3 document for a group version.
+func (h *Handler) openAPIDocument(gv schema.GroupVersion) (*spec3.OpenAPI, error) {
+	paths, err := h.client.Discovery().OpenAPIV3().Paths()
+	if err != nil {
+		return nil, err
+	}
+
+	key := "apis/" + gv.String()
+	if gv.Group == "" {
+		key = "api/" + gv.Version
+	}
+	groupVersion, ok := paths[key]
+	if !ok {
+		return nil, fmt.Errorf("the server does not publish an OpenAPI v3 schema for %s", gv.String())
+	}
+
+	raw, err := groupVersion.Schema("application/json")
+	if err != nil {
+		return nil, err
+	}
+	var doc spec3.OpenAPI
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI v3 schema: %w", err)
+	}
+	return &doc, nil
+}
+
+// findKindSchema returns the component schema tagged with the given GroupVersionKind.
+func findKindSchema(doc *spec3.OpenAPI, gvk schema.GroupVersionKind) *spec.Schema {
+	if doc.Components == nil {
+		return nil
+	}
+	for _, s := range doc.Components.Schemas {
+		gvks, ok := s.Extensions["x-kubernetes-group-version-kind"].([]interface{})
+		if !ok {
+			continue
+		}
+		for _, entry := range gvks {
+			m, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if m["group"] == gvk.Group && m["version"] == gvk.Version && m["kind"] == gvk.Kind {
+				return s
+			}
+		}
+	}
+	return nil
+}
+
+// resolveSchema follows $ref and single-element allOf wrappers to the
+// underlying schema. The description of the referencing field wins over the
+// description of the referenced type, like kubectl explain shows it.
+func resolveSchema(doc *spec3.OpenAPI, s *spec.Schema) *spec.Schema {
+	for i := 0; s != nil && i < 32; i++ {
+		var target *spec.Schema
+		switch {
+		case s.Ref.String() != "":
+			target = doc.Components.Schemas[strings.TrimPrefix(s.Ref.String(), "#/components/schemas/")]
+		case len(s.AllOf) == 1 && len(s.Properties) == 0:
+			target = &s.AllOf[0]
+		default:
+			return s
+		}
+		if target == nil {
+			return s
+		}
+		if s.Description != "" && s.Description != target.Description {
+			copied := *target
+			copied.Description = s.Description
+			target = &copied
+		}
+		s = target
+	}
+	return s
+}
+
+// fieldSchema returns the schema of a field, descending through arrays and maps.
+func fieldSchema(doc *spec3.OpenAPI, s *spec.Schema, field string) *spec.Schema {
+	s = elementSchema(doc, s)
+	prop, ok := s.Properties[field]
+	if !ok {
+		return nil
+	}
+	return resolveSchema(doc, &prop)
+}
+
+// elementSchema unwraps array items and map values so fields of the element
+// type can be addressed directly, like kubectl explain does.
+func elementSchema(doc *spec3.OpenAPI, s *spec.Schema) *spec.Schema {
+	for i := 0; i < 8; i++ {
+		switch {
+		case s.Type.Contains("array") && s.Items != nil && s.Items.Schema != nil:
+			s = resolveSchema(doc, s.Items.Schema)
+		case s.Type.Contains("object") && len(s.Properties) == 0 && s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil:
+			s = resolveSchema(doc, s.AdditionalProperties.Schema)
+		default:
+			return s
+		}
+	}
+	return s
+}
+
+// schemaTypeName renders a short Go-like type name for a schema.
+func schemaTypeName(doc *spec3.OpenAPI, s *spec.Schema) string {
+	if ref := s.Ref.String(); ref != "" {
+		parts := strings.Split(ref, ".")
+		return parts[len(parts)-1]
+	}
+	if len(s.AllOf) == 1 && s.AllOf[0].Ref.String() != "" {
+		return schemaTypeName(doc, &s.AllOf[0])
+	}
+	switch {
+	case s.Type.Contains("array") && s.Items != nil && s.Items.Schema != nil:
+		return "[]" + schemaTypeName(doc, s.Items.Schema)
+	case s.Type.Contains("object") && len(s.Properties) == 0 && s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil:
+		return "map[string]" + schemaTypeName(doc, s.AdditionalProperties.Schema)
+	case len(s.Type) > 0:
+		if s.Format != "" {
+			return fmt.Sprintf("%s (%s)", s.Type[0], s.Format)
+		}
+		return s.Type[0]
+	case s.Extensions["x-kubernetes-int-or-string"] == true:
+		return "int-or-string"
+	case s.Extensions["x-kubernetes-preserve-unknown-fields"] == true:
+		return "object (arbitrary)"
+	default:
+		return "object"
+	}
+}
+
+// explainResult is the structured output of explain.
+type explainResult struct {
+	Kind        string         `json:"kind"`
+	Version     string         `json:"version"`
+	Field       string         `json:"field,omitempty"`
+	Type        string         `json:"type"`
+	Description string         `json:"description,omitempty"`
+	Enum        []string       `json:"enum,omitempty"`
+	Required    []string       `json:"required,omitempty"`
+	Fields      []explainField `json:"fields,omitempty"`
+}
+
+type explainField struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required"`
+	Description string `json:"description,omitempty"`
+}
+
+// explainSchema describes the schema of a resource or field: its type,
+// description, enum values, required fields and sub-fields.
+func explainSchema(doc *spec3.OpenAPI, gvk schema.GroupVersionKind, segments []string, s *spec.Schema) explainResult {
+	result := explainResult{
+		Kind:        gvk.Kind,
+		Version:     gvk.GroupVersion().String(),
+		Field:       strings.Join(segments[1:], "."),
+		Type:        schemaTypeName(doc, s),
+		Description: s.Description,
+	}
+
+	element := elementSchema(doc, s)
+	enum := s.Enum
+	if len(enum) == 0 {
+		enum = element.Enum
+	}
+	for _, v := range enum {
+		result.Enum = append(result.Enum, fmt.Sprintf("%v", v))
+	}
+
+	if len(element.Required) > 0 {
+		result.Required = append([]string(nil), element.Required...)
+		sort.Strings(result.Required)
+	}
+
+	names := make([]string, 0, len(element.Properties))
+	for name := range element.Properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		prop := element.Properties[name]
+		result.Fields = append(result.Fields, explainField{
+			Name:        name,
+			Type:        schemaTypeName(doc, &prop),
+			Required:    containsString(element.Required, name),
+			Description: firstLine(resolveSchema(doc, &prop).Description),
+		})
+	}
+	return result
+}
+
+// formatExplain renders the explanation like kubectl explain.
+func formatExplain(e explainResult) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("KIND:     %s\n", e.Kind))
+	sb.WriteString(fmt.Sprintf("VERSION:  %s\n", e.Version))
+	if e.Field != "" {
+		sb.WriteString(fmt.Sprintf("FIELD:    %s\n", e.Field))
+	}
+	sb.WriteString(fmt.Sprintf("TYPE:     %s\n", e.Type))
+
+	if e.Description != "" {
+		sb.WriteString(fmt.Sprintf("\nDESCRIPTION:\n%s\n", e.Description))
+	}
+	if len(e.Enum) > 0 {
+		sb.WriteString(fmt.Sprintf("\nENUM:\n%s\n", strings.Join(e.Enum, ", ")))
+	}
+	if len(e.Required) > 0 {
+		sb.WriteString(fmt.Sprintf("\nREQUIRED FIELDS:\n%s\n", strings.Join(e.Required, ", ")))
+	}
+
+	if len(e.Fields) > 0 {
+		sb.WriteString("\nFIELDS:\n")
+		w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+		for _, f := range e.Fields {
+			marker := ""
+			if f.Required {
+				marker = " -required-"
+			}
+			_, _ = fmt.Fprintf(w, "  %s\t<%s>%s\t%s\n", f.Name, f.Type, marker, f.Description)
+		}
+		_ = w.Flush()
+	}
+
+	return sb.String()
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func firstLine(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		s = s[:i]
+	}
+	if runes := []rune(s); len(runes) > 120 {
+		s = string(runes[:117]) + "..."
+	}
+	return s
+}
@@ -0,0 +1,136 @@
+package tool
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/spec3"
+)
+
+const testOpenAPIDocument = `{
+  "openapi": "3.0.0",
+  "info": {"title": "Kubernetes", "version": "v1.33.0"},
+  "components": {
+    "schemas": {
+      "io.k8s.api.apps.v1.Deployment": {
+        "description": "Deployment enables declarative updates for Pods and ReplicaSets.",
+        "type": "object",
+        "properties": {
+          "spec": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.apps.v1.DeploymentSpec"}], "description": "Specification of the desired behavior of the Deployment."}
+        },
+        "x-kubernetes-group-version-kind": [{"group": "apps", "kind": "Deployment", "version": "v1"}]
+      },
+      "io.k8s.api.apps.v1.DeploymentSpec": {
+        "description": "DeploymentSpec is the specification of the desired behavior of the Deployment.",
+        "type": "object",
+        "required": ["selector", "template"],
+        "properties": {
+          "replicas": {"type": "integer", "format": "int32", "description": "Number of desired pods."},
+          "strategy": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.apps.v1.DeploymentStrategy"}], "description": "The deployment strategy to use."},
+          "containers": {"type": "array", "items": {"allOf": [{"$ref": "#/components/schemas/io.k8s.api.core.v1.Container"}]}}
+        }
+      },
+      "io.k8s.api.apps.v1.DeploymentStrategy": {
+        "type": "object",
+        "properties": {
+          "type": {"type": "string", "enum": ["Recreate", "RollingUpdate"], "description": "Type of deployment."}
+        }
+      },
+      "io.k8s.api.core.v1.Container": {
+        "type": "object",
+        "required": ["name"],
+        "properties": {
+          "name": {"type": "string"}
+        }
+      }
+    }
+  }
+}`
+
+func TestExplainSchemaWalk(t *testing.T) {
+	var doc spec3.OpenAPI
+	if err := json.Unmarshal([]byte(testOpenAPIDocument), &doc); err != nil {
+		t.Fatalf("failed to parse test document: %v", err)
+	}
+
+	root := findKindSchema(&doc, schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"})
+	if root == nil {
+		t.Fatal("findKindSchema() did not find the Deployment schema")
+	}
+
+	tests := []struct {
+		path     []string
+		wantType string
+		wantDesc string
+		wantNil  bool
+	}{
+		{[]string{"spec"}, "object", "Specification of the desired behavior of the Deployment.", false},
+		{[]string{"spec", "replicas"}, "integer (int32)", "Number of desired pods.", false},
+		{[]string{"spec", "strategy", "type"}, "string", "Type of deployment.", false},
+		{[]string{"spec", "containers"}, "[]Container", "", false},
+		{[]string{"spec", "containers", "name"}, "string", "", false},
+		{[]string{"spec", "missing"}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path[len(tt.path)-1], func(t *testing.T) {
+			current := resolveSchema(&doc, root)
+			for _, field := range tt.path {
+				if current = fieldSchema(&doc, current, field); current == nil {
+					break
+				}
+			}
+			if tt.wantNil {
+				if current != nil {
+					t.Errorf("fieldSchema() expected nil for %v", tt.path)
+				}
+				return
+			}
+			if current == nil {
+				t.Fatalf("fieldSchema() returned nil for %v", tt.path)
+			}
+			if got := schemaTypeName(&doc, current); got != tt.wantType {
+				t.Errorf("schemaTypeName() = %v, want %v", got, tt.wantType)
+			}
+			if current.Description != tt.wantDesc {
+				t.Errorf("description = %q, want %q", current.Description, tt.wantDesc)
+			}
+		})
+	}
+}
+
+func TestExplainSchema(t *testing.T) {
+	var doc spec3.OpenAPI
+	if err := json.Unmarshal([]byte(testOpenAPIDocument), &doc); err != nil {
+		t.Fatalf("failed to parse test document: %v", err)
+	}
+	gvk := schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}
+	spec := fieldSchema(&doc, resolveSchema(&doc, findKindSchema(&doc, gvk)), "spec")
+
+	got := explainSchema(&doc, gvk, []string{"deployment", "spec"}, spec)
+	want := explainResult{
+		Kind:        "Deployment",
+		Version:     "apps/v1",
+		Field:       "spec",
+		Type:        "object",
+		Description: "Specification of the desired behavior of the Deployment.",
+		Required:    []string{"selector", "template"},
+		Fields: []explainField{
+			{Name: "containers", Type: "[]Container"},
+			{Name: "replicas", Type: "integer (int32)", Description: "Number of desired pods."},
+			{Name: "strategy", Type: "DeploymentStrategy", Description: "The deployment strategy to use."},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("explainSchema() = %+v, want %+v", got, want)
+	}
+
+	text := formatExplain(got)
+	for _, line := range []string{"FIELD:    spec", "REQUIRED FIELDS:", "selector, template", "  replicas    <integer (int32)>     Number of desired pods."} {
+		if !strings.Contains(text, line) {
+			t.Errorf("formatExplain() has no %q:\n%s", line, text)
+		}
+	}
+}
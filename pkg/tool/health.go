@@ -0,0 +1,24 @@
+package tool
+
+import (
+	"context"
+
+	"github.com/idebeijer/kube-mcp-server/pkg/kube"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func (h *Handler) registerHealth(m *server.MCPServer) {
+	h.addTool(m, mcp.NewTool("cluster_health",
+		mcp.WithDescription("Summarize cluster health: API server version and readiness, node conditions, pods not Running/Succeeded grouped by reason, workloads with unavailable replicas, pending PVCs, failed Jobs and recent Warning events"),
+		mcp.WithOutputSchema[kube.ClusterHealth](),
+	), h.clusterHealthHandler)
+}
+
+func (h *Handler) clusterHealthHandler(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	result, err := structuredResult(kube.GetClusterHealth(ctx, h.client))
+	if err != nil {
+		return mcp.NewToolResultErrorFromErr("failed to marshal cluster health", err), nil
+	}
+	return result, nil
+}
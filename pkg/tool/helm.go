@@ -0,0 +1,415 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"sigs.k8s.io/yaml"
+)
+
+const redacted = "REDACTED"
+
+// sensitiveValueKey matches values keys that likely hold credentials.
+var sensitiveValueKey = regexp.MustCompile(`(?i)(password|passwd|secret|token|api[-_]?key|access[-_]?key|private[-_]?key|credential|^auth$)`)
+
+// helmReleaseSummary describes a release revision in the structured output of
+// the helm tools.
+type helmReleaseSummary struct {
+	Name          string `json:"name"`
+	Namespace     string `json:"namespace"`
+	Revision      int    `json:"revision"`
+	Status        string `json:"status"`
+	Chart         string `json:"chart"`
+	AppVersion    string `json:"appVersion,omitempty"`
+	FirstDeployed string `json:"firstDeployed,omitempty"`
+	Updated       string `json:"updated,omitempty"`
+	Description   string `json:"description,omitempty"`
+	Notes         string `json:"notes,omitempty"`
+}
+
+func (r *helmRelease) summary() helmReleaseSummary {
+	return helmReleaseSummary{
+		Name:          r.Name,
+		Namespace:     r.Namespace,
+		Revision:      r.Version,
+		Status:        r.Info.Status,
+		Chart:         r.chartRef(),
+		AppVersion:    r.Chart.Metadata.AppVersion,
+		FirstDeployed: formatHelmTime(r.Info.FirstDeployed),
+		Updated:       formatHelmTime(r.Info.LastDeployed),
+		Description:   r.Info.Description,
+		Notes:         strings.TrimSpace(r.Info.Notes),
+	}
+}
+
+// helmReleasesResult is the structured output of helm_list and helm_history.
+type helmReleasesResult struct {
+	Releases []helmReleaseSummary `json:"releases"`
+}
+
+// helmValuesResult is the structured output of helm_values.
+type helmValuesResult struct {
+	Release string                 `json:"release"`
+	Values  map[string]interface{} `json:"values"`
+}
+
+// helmManifestResult is the structured output of helm_manifest.
+type helmManifestResult struct {
+	Release  string `json:"release"`
+	Manifest string `json:"manifest"`
+}
+
+func (h *Handler) registerHelm(m *server.MCPServer) {
+	h.addTool(m, mcp.NewTool("helm_list",
+		mcp.WithDescription("List Helm v3 releases by reading Helm's release storage (Secrets and ConfigMaps), showing revision, status, chart and app version"),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to list releases in (leave empty for all namespaces)"),
+		),
+		mcp.WithString("status",
+			mcp.Description("Only list releases with this status (e.g., 'deployed', 'failed', 'pending-upgrade')"),
+		),
+		mcp.WithOutputSchema[helmReleasesResult](),
+	), mcp.NewTypedToolHandler[HelmListArgs](h.helmListHandler()))
+
+	h.addTool(m, mcp.NewTool("helm_status",
+		append(helmReleaseOptions("Show the status of a Helm release: revision, status, chart, app version, deployment times, description and notes"),
+			mcp.WithOutputSchema[helmReleaseSummary](),
+		)...,
+	), mcp.NewTypedToolHandler[HelmReleaseArgs](h.helmStatusHandler()))
+
+	h.addTool(m, mcp.NewTool("helm_history",
+		mcp.WithDescription("Show the revision history of a Helm release"),
+		mcp.WithString("name", mcp.Description("Name of the release"), mcp.Required()),
+		mcp.WithString("namespace", mcp.Description("Namespace of the release (defaults to the current context's namespace)")),
+		mcp.WithOutputSchema[helmReleasesResult](),
+	), mcp.NewTypedToolHandler[HelmReleaseArgs](h.helmHistoryHandler()))
+
+	h.addTool(m, mcp.NewTool("helm_values",
+		append(helmReleaseOptions("Show the user-supplied values of a Helm release as YAML, with likely secrets redacted"),
+			mcp.WithBoolean("all",
+				mcp.Description("Show the computed values (chart defaults merged with user-supplied values) instead of only user-supplied values"),
+				mcp.DefaultBool(false),
+			),
+			mcp.WithOutputSchema[helmValuesResult](),
+		)...,
+	), mcp.NewTypedToolHandler[HelmReleaseArgs](h.helmValuesHandler()))
+
+	h.addTool(m, mcp.NewTool("helm_manifest",
+		append(helmReleaseOptions("Show the rendered manifest of a Helm release, with Secret data redacted"),
+			mcp.WithOutputSchema[helmManifestResult](),
+		)...,
+	), mcp.NewTypedToolHandler[HelmReleaseArgs](h.helmManifestHandler()))
+}
+
+// helmReleaseOptions returns the description and the arguments selecting a
+// release revision shared by the helm tools.
+func helmReleaseOptions(description string) []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithDescription(description),
+		mcp.WithString("name",
+			mcp.Description("Name of the release"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the release (defaults to the current context's namespace)"),
+		),
+		mcp.WithNumber("revision",
+			mcp.Description("Revision of the release (defaults to the latest)"),
+		),
+	}
+}
+
+type HelmListArgs struct {
+	Namespace string `json:"namespace,omitempty"`
+	Status    string `json:"status,omitempty"`
+}
+
+type HelmReleaseArgs struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Revision  int    `json:"revision,omitempty"`
+	All       bool   `json:"all,omitempty"`
+}
+
+func (h *Handler) helmListHandler() mcp.TypedToolHandlerFunc[HelmListArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args HelmListArgs,
+	) (*mcp.CallToolResult, error) {
+		releases, err := h.listHelmReleases(ctx, args.Namespace, "")
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to list helm releases", err), nil
+		}
+
+		var sb strings.Builder
+		w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "NAME\tNAMESPACE\tREVISION\tUPDATED\tSTATUS\tCHART\tAPP VERSION")
+		structured := helmReleasesResult{Releases: []helmReleaseSummary{}}
+		for _, r := range latestHelmReleases(releases) {
+			if args.Status != "" && r.Info.Status != args.Status {
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+				r.Name, r.Namespace, r.Version, formatHelmTime(r.Info.LastDeployed), r.Info.Status, r.chartRef(), r.Chart.Metadata.AppVersion)
+			structured.Releases = append(structured.Releases, r.summary())
+		}
+		_ = w.Flush()
+
+		return mcp.NewToolResultStructured(structured, fmt.Sprintf("Found %d helm releases\n\n%s", len(structured.Releases), sb.String())), nil
+	}
+}
+
+func (h *Handler) helmStatusHandler() mcp.TypedToolHandlerFunc[HelmReleaseArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args HelmReleaseArgs,
+	) (*mcp.CallToolResult, error) {
+		release, err := h.helmRelease(ctx, args)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to get helm release", err), nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("NAME: %s\n", release.Name))
+		sb.WriteString(fmt.Sprintf("NAMESPACE: %s\n", release.Namespace))
+		sb.WriteString(fmt.Sprintf("STATUS: %s\n", release.Info.Status))
+		sb.WriteString(fmt.Sprintf("REVISION: %d\n", release.Version))
+		sb.WriteString(fmt.Sprintf("CHART: %s\n", release.chartRef()))
+		sb.WriteString(fmt.Sprintf("APP VERSION: %s\n", release.Chart.Metadata.AppVersion))
+		sb.WriteString(fmt.Sprintf("FIRST DEPLOYED: %s\n", formatHelmTime(release.Info.FirstDeployed)))
+		sb.WriteString(fmt.Sprintf("LAST DEPLOYED: %s\n", formatHelmTime(release.Info.LastDeployed)))
+		sb.WriteString(fmt.Sprintf("DESCRIPTION: %s\n", release.Info.Description))
+		if notes := strings.TrimSpace(release.Info.Notes); notes != "" {
+			sb.WriteString(fmt.Sprintf("\nNOTES:\n%s\n", notes))
+		}
+		return mcp.NewToolResultStructured(release.summary(), sb.String()), nil
+	}
+}
+
+func (h *Handler) helmHistoryHandler() mcp.TypedToolHandlerFunc[HelmReleaseArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args HelmReleaseArgs,
+	) (*mcp.CallToolResult, error) {
+		releases, err := h.helmRevisions(ctx, args)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to get helm release history", err), nil
+		}
+
+		var sb strings.Builder
+		w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(w, "REVISION\tUPDATED\tSTATUS\tCHART\tAPP VERSION\tDESCRIPTION")
+		structured := helmReleasesResult{Releases: []helmReleaseSummary{}}
+		for _, r := range releases {
+			_, _ = fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
+				r.Version, formatHelmTime(r.Info.LastDeployed), r.Info.Status, r.chartRef(), r.Chart.Metadata.AppVersion, r.Info.Description)
+			summary := r.summary()
+			summary.Notes = ""
+			structured.Releases = append(structured.Releases, summary)
+		}
+		_ = w.Flush()
+		return mcp.NewToolResultStructured(structured, sb.String()), nil
+	}
+}
+
+func (h *Handler) helmValuesHandler() mcp.TypedToolHandlerFunc[HelmReleaseArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args HelmReleaseArgs,
+	) (*mcp.CallToolResult, error) {
+		release, err := h.helmRelease(ctx, args)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to get helm release", err), nil
+		}
+
+		values := release.Config
+		if args.All {
+			values = mergeValues(release.Chart.Values, release.Config)
+		}
+		if len(values) == 0 {
+			structured := helmValuesResult{Release: release.String(), Values: map[string]interface{}{}}
+			return mcp.NewToolResultStructured(structured, fmt.Sprintf("Release %s has no user-supplied values", release)), nil
+		}
+
+		values = redactValues(values)
+		out, err := yaml.Marshal(values)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to marshal values", err), nil
+		}
+		structured := helmValuesResult{Release: release.String(), Values: values}
+		return mcp.NewToolResultStructured(structured, fmt.Sprintf("Values of release %s:\n\n%s", release, out)), nil
+	}
+}
+
+func (h *Handler) helmManifestHandler() mcp.TypedToolHandlerFunc[HelmReleaseArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args HelmReleaseArgs,
+	) (*mcp.CallToolResult, error) {
+		release, err := h.helmRelease(ctx, args)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to get helm release", err), nil
+		}
+		manifest := redactManifestSecrets(release.Manifest)
+		return mcp.NewToolResultStructured(helmManifestResult{Release: release.String(), Manifest: manifest}, manifest), nil
+	}
+}
+
+// helmRevisions returns all stored revisions of a release, oldest first.
+func (h *Handler) helmRevisions(ctx context.Context, args HelmReleaseArgs) ([]*helmRelease, error) {
+	namespace := args.Namespace
+	if namespace == "" {
+		namespace = h.defaultNamespace
+	}
+	releases, err := h.listHelmReleases(ctx, namespace, "name="+args.Name)
+	if err != nil {
+		return nil, err
+	}
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("release %s not found in namespace %s", args.Name, namespace)
+	}
+	return releases, nil
+}
+
+// helmRelease returns the requested revision of a release, or the latest.
+func (h *Handler) helmRelease(ctx context.Context, args HelmReleaseArgs) (*helmRelease, error) {
+	releases, err := h.helmRevisions(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if args.Revision == 0 {
+		return releases[len(releases)-1], nil
+	}
+	for _, r := range releases {
+		if r.Version == args.Revision {
+			return r, nil
+		}
+	}
+	return nil, fmt.Errorf("revision %d of release %s not found", args.Revision, args.Name)
+}
+
+func (r *helmRelease) chartRef() string {
+	return r.Chart.Metadata.Name + "-" + r.Chart.Metadata.Version
+}
+
+func formatHelmTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// mergeValues deep merges overrides into a copy of base, like Helm coalesces
+// user-supplied values with chart defaults.
+func mergeValues(base, overrides map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(base))
+	for k, v := range base {
+		result[k] = v
+	}
+	for k, v := range overrides {
+		baseMap, baseIsMap := result[k].(map[string]interface{})
+		overrideMap, overrideIsMap := v.(map[string]interface{})
+		if baseIsMap && overrideIsMap {
+			result[k] = mergeValues(baseMap, overrideMap)
+			continue
+		}
+		result[k] = v
+	}
+	return result
+}
+
+// redactValues returns a copy of the values with non-empty strings and numbers
+// under sensitive looking keys replaced. Booleans are kept, so toggles like
+// "existingSecret.enabled" stay readable.
+func redactValues(values map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(values))
+	for k, v := range values {
+		switch value := v.(type) {
+		case map[string]interface{}:
+			result[k] = redactValues(value)
+		case []interface{}:
+			result[k] = redactList(value, sensitiveValueKey.MatchString(k))
+		case bool, nil:
+			result[k] = v
+		default:
+			if sensitiveValueKey.MatchString(k) && v != "" {
+				result[k] = redacted
+			} else {
+				result[k] = v
+			}
+		}
+	}
+	return result
+}
+
+func redactList(list []interface{}, sensitive bool) []interface{} {
+	result := make([]interface{}, len(list))
+	for i, item := range list {
+		switch value := item.(type) {
+		case map[string]interface{}:
+			result[i] = redactValues(value)
+		case []interface{}:
+			result[i] = redactList(value, sensitive)
+		case bool, nil:
+			result[i] = item
+		default:
+			if sensitive {
+				result[i] = redacted
+			} else {
+				result[i] = item
+			}
+		}
+	}
+	return result
+}
+
+// redactManifestSecrets replaces the data of every Secret in a rendered
+// manifest. Other documents are returned unchanged.
+func redactManifestSecrets(manifest string) string {
+	docs := strings.Split("\n"+manifest, "\n---")
+	for i, doc := range docs {
+		var obj map[string]interface{}
+		if err := yaml.Unmarshal([]byte(doc), &obj); err != nil || obj["kind"] != "Secret" {
+			continue
+		}
+		for _, field := range []string{"data", "stringData"} {
+			data, ok := obj[field].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for k := range data {
+				data[k] = redacted
+			}
+		}
+		out, err := yaml.Marshal(obj)
+		if err != nil {
+			continue
+		}
+
+		// Keep the "# Source:" comment Helm puts in front of every document.
+		var comments []string
+		for _, line := range strings.Split(strings.TrimLeft(doc, "\n"), "\n") {
+			if !strings.HasPrefix(line, "#") {
+				break
+			}
+			comments = append(comments, line)
+		}
+		rendered := string(out)
+		if len(comments) > 0 {
+			rendered = strings.Join(comments, "\n") + "\n" + rendered
+		}
+		docs[i] = "\n" + strings.TrimRight(rendered, "\n")
+	}
+	return strings.TrimPrefix(strings.Join(docs, "\n---"), "\n")
+}
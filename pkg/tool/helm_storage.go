@@ -0,0 +1,166 @@
+package tool
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// helmRelease mirrors the parts of Helm v3's release.Release that are stored
+// in the release Secrets and ConfigMaps.
+type helmRelease struct {
+	Name      string                 `json:"name"`
+	Namespace string                 `json:"namespace"`
+	Version   int                    `json:"version"`
+	Info      helmReleaseInfo        `json:"info"`
+	Chart     helmChart              `json:"chart"`
+	Config    map[string]interface{} `json:"config"`
+	Manifest  string                 `json:"manifest"`
+}
+
+type helmReleaseInfo struct {
+	FirstDeployed time.Time `json:"first_deployed"`
+	LastDeployed  time.Time `json:"last_deployed"`
+	Description   string    `json:"description"`
+	Status        string    `json:"status"`
+	Notes         string    `json:"notes"`
+}
+
+type helmChart struct {
+	Metadata helmChartMetadata      `json:"metadata"`
+	Values   map[string]interface{} `json:"values"`
+}
+
+type helmChartMetadata struct {
+	Name       string `json:"name"`
+	Version    string `json:"version"`
+	AppVersion string `json:"appVersion"`
+}
+
+// gzipMagic is the header Helm's storage driver checks to detect compressed payloads.
+var gzipMagic = []byte{0x1f, 0x8b, 0x08}
+
+// decodeHelmRelease decodes a release payload as stored by Helm v3: base64
+// encoded, optionally gzipped JSON.
+func decodeHelmRelease(data string) (*helmRelease, error) {
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to base64 decode release: %w", err)
+	}
+
+	if bytes.HasPrefix(raw, gzipMagic) {
+		reader, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzipped release: %w", err)
+		}
+		defer func() { _ = reader.Close() }()
+		if raw, err = io.ReadAll(reader); err != nil {
+			return nil, fmt.Errorf("failed to decompress release: %w", err)
+		}
+	}
+
+	var release helmRelease
+	if err := json.Unmarshal(raw, &release); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal release: %w", err)
+	}
+	return &release, nil
+}
+
+// listHelmReleases reads Helm v3 release storage (Secrets and ConfigMaps
+// labelled owner=helm) in the namespace, or all namespaces when empty. The
+// extra label selector narrows the query, e.g. "name=foo" or "status=deployed".
+// Releases are sorted by namespace, name and revision.
+func (h *Handler) listHelmReleases(ctx context.Context, namespace, selector string) ([]*helmRelease, error) {
+	labelSelector := "owner=helm"
+	if selector != "" {
+		labelSelector += "," + selector
+	}
+	opts := metav1.ListOptions{LabelSelector: labelSelector}
+
+	secrets, err := h.client.CoreV1().Secrets(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list helm release secrets: %w", err)
+	}
+	configMaps, err := h.client.CoreV1().ConfigMaps(namespace).List(ctx, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list helm release configmaps: %w", err)
+	}
+	releases := decodeHelmReleases(secrets.Items, configMaps.Items)
+
+	sort.Slice(releases, func(i, j int) bool {
+		a, b := releases[i], releases[j]
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Version < b.Version
+	})
+	return releases, nil
+}
+
+// decodeHelmReleases decodes the releases stored in the Secrets and
+// ConfigMaps. Objects that don't hold a valid release, e.g. because another
+// tool labelled them owner=helm, are logged and skipped so they don't hide the
+// other releases.
+func decodeHelmReleases(secrets []corev1.Secret, configMaps []corev1.ConfigMap) []*helmRelease {
+	var releases []*helmRelease
+	for _, s := range secrets {
+		if s.Type != "helm.sh/release.v1" {
+			continue
+		}
+		release, err := decodeHelmRelease(string(s.Data["release"]))
+		if err != nil {
+			log.Warn().Err(err).Str("secret", s.Namespace+"/"+s.Name).Msg("Skipping undecodable Helm release")
+			continue
+		}
+		releases = append(releases, release)
+	}
+	for _, c := range configMaps {
+		release, err := decodeHelmRelease(c.Data["release"])
+		if err != nil {
+			log.Warn().Err(err).Str("configmap", c.Namespace+"/"+c.Name).Msg("Skipping undecodable Helm release")
+			continue
+		}
+		releases = append(releases, release)
+	}
+	return releases
+}
+
+// latestHelmReleases keeps only the highest revision of every release.
+func latestHelmReleases(releases []*helmRelease) []*helmRelease {
+	latest := map[string]*helmRelease{}
+	var order []string
+	for _, r := range releases {
+		key := r.Namespace + "/" + r.Name
+		current, ok := latest[key]
+		if !ok {
+			order = append(order, key)
+		}
+		if !ok || r.Version > current.Version {
+			latest[key] = r
+		}
+	}
+
+	result := make([]*helmRelease, 0, len(order))
+	for _, key := range order {
+		result = append(result, latest[key])
+	}
+	return result
+}
+
+func (r *helmRelease) String() string {
+	return r.Namespace + "/" + r.Name + " (revision " + strconv.Itoa(r.Version) + ")"
+}
@@ -0,0 +1,146 @@
+package tool
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDecodeHelmRelease(t *testing.T) {
+	payload := `{"name":"web","namespace":"prod","version":3,"info":{"status":"deployed"},"chart":{"metadata":{"name":"nginx","version":"1.2.3","appVersion":"1.27"}},"config":{"replicas":2}}`
+
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	_, _ = w.Write([]byte(payload))
+	_ = w.Close()
+
+	for name, data := range map[string][]byte{"gzipped": gz.Bytes(), "plain": []byte(payload)} {
+		t.Run(name, func(t *testing.T) {
+			release, err := decodeHelmRelease(base64.StdEncoding.EncodeToString(data))
+			if err != nil {
+				t.Fatalf("decodeHelmRelease() error = %v", err)
+			}
+			if release.String() != "prod/web (revision 3)" || release.chartRef() != "nginx-1.2.3" || release.Info.Status != "deployed" {
+				t.Errorf("decodeHelmRelease() = %+v", release)
+			}
+		})
+	}
+
+	if _, err := decodeHelmRelease("not base64!"); err == nil {
+		t.Error("decodeHelmRelease() expected an error for invalid input")
+	}
+}
+
+func TestDecodeHelmReleasesSkipsInvalid(t *testing.T) {
+	valid := base64.StdEncoding.EncodeToString([]byte(`{"name":"web","namespace":"prod","version":1}`))
+	secrets := []corev1.Secret{
+		{ObjectMeta: metav1.ObjectMeta{Name: "sh.helm.release.v1.web.v1", Namespace: "prod"},
+			Type: "helm.sh/release.v1", Data: map[string][]byte{"release": []byte(valid)}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "truncated", Namespace: "prod"},
+			Type: "helm.sh/release.v1", Data: map[string][]byte{"release": []byte(valid[:10])}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "other-tool", Namespace: "prod"},
+			Type: "Opaque", Data: map[string][]byte{"password": []byte("x")}},
+	}
+	configMaps := []corev1.ConfigMap{
+		{ObjectMeta: metav1.ObjectMeta{Name: "third-party", Namespace: "prod"}, Data: map[string]string{"config": "x"}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "web.v2", Namespace: "prod"}, Data: map[string]string{"release": valid}},
+	}
+
+	releases := decodeHelmReleases(secrets, configMaps)
+	if len(releases) != 2 {
+		t.Fatalf("decodeHelmReleases() returned %d releases, want the 2 valid ones", len(releases))
+	}
+}
+
+func TestLatestHelmReleases(t *testing.T) {
+	releases := []*helmRelease{
+		{Name: "a", Namespace: "x", Version: 1},
+		{Name: "a", Namespace: "x", Version: 2},
+		{Name: "a", Namespace: "y", Version: 1},
+	}
+	latest := latestHelmReleases(releases)
+	if len(latest) != 2 || latest[0].Version != 2 || latest[1].Namespace != "y" {
+		t.Errorf("latestHelmReleases() = %v", latest)
+	}
+}
+
+func TestRedactValues(t *testing.T) {
+	values := map[string]interface{}{
+		"replicas": 2,
+		"auth":     "hunter2",
+		"postgresql": map[string]interface{}{
+			"password":       "hunter2",
+			"existingSecret": "",
+			"tokens":         []interface{}{"a", "b"},
+		},
+		"apiKeyEnabled": true,
+		"image":         map[string]interface{}{"tag": "1.0"},
+	}
+	want := map[string]interface{}{
+		"replicas": 2,
+		"auth":     redacted,
+		"postgresql": map[string]interface{}{
+			"password":       redacted,
+			"existingSecret": "",
+			"tokens":         []interface{}{redacted, redacted},
+		},
+		"apiKeyEnabled": true,
+		"image":         map[string]interface{}{"tag": "1.0"},
+	}
+	if got := redactValues(values); !reflect.DeepEqual(got, want) {
+		t.Errorf("redactValues() = %v, want %v", got, want)
+	}
+}
+
+func TestMergeValues(t *testing.T) {
+	base := map[string]interface{}{"image": map[string]interface{}{"repository": "nginx", "tag": "1.0"}, "replicas": 1}
+	overrides := map[string]interface{}{"image": map[string]interface{}{"tag": "2.0"}}
+	want := map[string]interface{}{"image": map[string]interface{}{"repository": "nginx", "tag": "2.0"}, "replicas": 1}
+	if got := mergeValues(base, overrides); !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeValues() = %v, want %v", got, want)
+	}
+}
+
+func TestRedactManifestSecrets(t *testing.T) {
+	manifest := `---
+# Source: app/templates/secret.yaml
+apiVersion: v1
+kind: Secret
+metadata:
+  name: app
+data:
+  password: aHVudGVyMg==
+---
+# Source: app/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+data:
+  password: visible`
+
+	want := `---
+# Source: app/templates/secret.yaml
+apiVersion: v1
+data:
+  password: REDACTED
+kind: Secret
+metadata:
+  name: app
+---
+# Source: app/templates/configmap.yaml
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: app
+data:
+  password: visible`
+	if got := redactManifestSecrets(manifest); got != want {
+		t.Errorf("redactManifestSecrets() =\n%s\nwant\n%s", got, want)
+	}
+}
@@ -0,0 +1,380 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/idebeijer/kube-mcp-server/pkg/kube"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+)
+
+const (
+	// defaultCronJobRuns is the number of runs cronjob_runs shows by default.
+	defaultCronJobRuns = 10
+	// maxJobNameLength leaves room for the pod name suffix, like the CronJob controller.
+	maxJobNameLength = 52
+)
+
+func (h *Handler) registerJobs(m *server.MCPServer) {
+	h.addTool(m, mcp.NewTool("create_job_from_cronjob",
+		mcp.WithDescription("Trigger a CronJob manually by creating a Job from its job template (like 'kubectl create job --from=cronjob/NAME')"),
+		mcp.WithString("cronjob",
+			mcp.Description("Name of the CronJob"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the CronJob (defaults to the current context's namespace)"),
+		),
+		mcp.WithString("job_name",
+			mcp.Description("Name of the Job to create (defaults to '<cronjob>-manual-<random>')"),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Validate the Job on the server without creating it"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithOutputSchema[createJobResult](),
+	), mcp.NewTypedToolHandler[CreateJobFromCronJobArgs](h.createJobFromCronJobHandler()))
+
+	h.addTool(m, mcp.NewTool("suspend_cronjob",
+		append(cronJobNameOptions("Suspend a CronJob so no new Jobs are scheduled; running Jobs are not affected"),
+			mcp.WithOutputSchema[cronJobSuspendResult](),
+		)...,
+	), mcp.NewTypedToolHandler[CronJobArgs](h.setCronJobSuspendHandler(true)))
+
+	h.addTool(m, mcp.NewTool("resume_cronjob",
+		append(cronJobNameOptions("Resume a suspended CronJob"),
+			mcp.WithOutputSchema[cronJobSuspendResult](),
+		)...,
+	), mcp.NewTypedToolHandler[CronJobArgs](h.setCronJobSuspendHandler(false)))
+
+	h.addTool(m, mcp.NewTool("cronjob_runs",
+		append(cronJobNameOptions("Show the schedule of a CronJob and its recent runs (Jobs) with status, duration, completions and failure reason"),
+			mcp.WithNumber("limit",
+				mcp.Description(fmt.Sprintf("Maximum number of runs to show, newest first (default %d)", defaultCronJobRuns)),
+			),
+			mcp.WithOutputSchema[cronJobRunsResult](),
+		)...,
+	), mcp.NewTypedToolHandler[CronJobArgs](h.cronJobRunsHandler()))
+}
+
+// cronJobNameOptions returns the description and the arguments selecting a
+// CronJob shared by the cronjob tools.
+func cronJobNameOptions(description string) []mcp.ToolOption {
+	return []mcp.ToolOption{
+		mcp.WithDescription(description),
+		mcp.WithString("name",
+			mcp.Description("Name of the CronJob"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the CronJob (defaults to the current context's namespace)"),
+		),
+	}
+}
+
+type CreateJobFromCronJobArgs struct {
+	CronJob   string `json:"cronjob"`
+	Namespace string `json:"namespace,omitempty"`
+	JobName   string `json:"job_name,omitempty"`
+	DryRun    bool   `json:"dry_run"`
+}
+
+type CronJobArgs struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	Limit     int    `json:"limit,omitempty"`
+}
+
+// createJobResult is the structured output of create_job_from_cronjob.
+type createJobResult struct {
+	Job       string `json:"job"`
+	CronJob   string `json:"cronJob"`
+	Namespace string `json:"namespace"`
+	DryRun    bool   `json:"dryRun"`
+}
+
+// cronJobSuspendResult is the structured output of suspend_cronjob and
+// resume_cronjob.
+type cronJobSuspendResult struct {
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+	Suspended  bool   `json:"suspended"`
+	ActiveJobs int    `json:"activeJobs"`
+}
+
+func (h *Handler) createJobFromCronJobHandler() mcp.TypedToolHandlerFunc[CreateJobFromCronJobArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args CreateJobFromCronJobArgs,
+	) (*mcp.CallToolResult, error) {
+		namespace := args.Namespace
+		if namespace == "" {
+			namespace = h.defaultNamespace
+		}
+
+		cronJob, err := h.client.BatchV1().CronJobs(namespace).Get(ctx, args.CronJob, metav1.GetOptions{})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to get cronjob", err), nil
+		}
+
+		opts := metav1.CreateOptions{FieldManager: h.fieldManager}
+		if args.DryRun {
+			opts.DryRun = []string{metav1.DryRunAll}
+		}
+		job, err := h.client.BatchV1().Jobs(namespace).Create(ctx, jobFromCronJob(cronJob, args.JobName), opts)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to create job", err), nil
+		}
+
+		suffix := ""
+		if args.DryRun {
+			suffix = " (server dry run)"
+		}
+		return mcp.NewToolResultStructured(
+			createJobResult{Job: job.Name, CronJob: cronJob.Name, Namespace: namespace, DryRun: args.DryRun},
+			fmt.Sprintf("job.batch/%s created from cronjob %s in namespace %s%s", job.Name, cronJob.Name, namespace, suffix),
+		), nil
+	}
+}
+
+// jobFromCronJob builds a Job from the CronJob's template the same way kubectl
+// does, marking it as manually instantiated and owned by the CronJob.
+func jobFromCronJob(cronJob *batchv1.CronJob, name string) *batchv1.Job {
+	if name == "" {
+		base := cronJob.Name + "-manual"
+		if len(base) > maxJobNameLength-6 {
+			base = base[:maxJobNameLength-6]
+		}
+		name = base + "-" + utilrand.String(5)
+	}
+
+	annotations := map[string]string{"cronjob.kubernetes.io/instantiate": "manual"}
+	for k, v := range cronJob.Spec.JobTemplate.Annotations {
+		annotations[k] = v
+	}
+
+	return &batchv1.Job{
+		TypeMeta: metav1.TypeMeta{APIVersion: batchv1.SchemeGroupVersion.String(), Kind: "Job"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       cronJob.Namespace,
+			Annotations:     annotations,
+			Labels:          cronJob.Spec.JobTemplate.Labels,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(cronJob, batchv1.SchemeGroupVersion.WithKind("CronJob"))},
+		},
+		Spec: *cronJob.Spec.JobTemplate.Spec.DeepCopy(),
+	}
+}
+
+func (h *Handler) setCronJobSuspendHandler(suspend bool) mcp.TypedToolHandlerFunc[CronJobArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args CronJobArgs,
+	) (*mcp.CallToolResult, error) {
+		namespace := args.Namespace
+		if namespace == "" {
+			namespace = h.defaultNamespace
+		}
+
+		patch := fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend)
+		cronJob, err := h.client.BatchV1().CronJobs(namespace).Patch(ctx, args.Name, types.MergePatchType, []byte(patch),
+			metav1.PatchOptions{FieldManager: h.fieldManager})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to patch cronjob", err), nil
+		}
+
+		state := "resumed"
+		if suspend {
+			state = "suspended"
+		}
+		response := fmt.Sprintf("cronjob.batch/%s %s in namespace %s", cronJob.Name, state, namespace)
+		if suspend && len(cronJob.Status.Active) > 0 {
+			response += fmt.Sprintf("\n%d active job(s) keep running", len(cronJob.Status.Active))
+		}
+		return mcp.NewToolResultStructured(cronJobSuspendResult{
+			Name:       cronJob.Name,
+			Namespace:  namespace,
+			Suspended:  suspend,
+			ActiveJobs: len(cronJob.Status.Active),
+		}, response), nil
+	}
+}
+
+func (h *Handler) cronJobRunsHandler() mcp.TypedToolHandlerFunc[CronJobArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args CronJobArgs,
+	) (*mcp.CallToolResult, error) {
+		namespace := args.Namespace
+		if namespace == "" {
+			namespace = h.defaultNamespace
+		}
+		limit := args.Limit
+		if limit <= 0 {
+			limit = defaultCronJobRuns
+		}
+
+		cronJob, err := h.client.BatchV1().CronJobs(namespace).Get(ctx, args.Name, metav1.GetOptions{})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to get cronjob", err), nil
+		}
+		jobs, err := h.client.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to list jobs", err), nil
+		}
+
+		now := time.Now()
+		runs := cronJobRuns(cronJob, jobs.Items, limit, now)
+		return mcp.NewToolResultStructured(runs, runs.String(now)), nil
+	}
+}
+
+// cronJobRunsResult is the structured output of cronjob_runs. Runs holds the
+// newest runs up to the limit; OlderRuns counts those left out.
+type cronJobRunsResult struct {
+	Name              string       `json:"name"`
+	Namespace         string       `json:"namespace"`
+	Schedule          string       `json:"schedule"`
+	TimeZone          string       `json:"timeZone,omitempty"`
+	Suspended         bool         `json:"suspended"`
+	NextSchedule      string       `json:"nextSchedule,omitempty"`
+	NextScheduleError string       `json:"nextScheduleError,omitempty"`
+	LastSchedule      string       `json:"lastSchedule,omitempty"`
+	LastSuccessful    string       `json:"lastSuccessful,omitempty"`
+	Active            int          `json:"active"`
+	Runs              []cronJobRun `json:"runs"`
+	OlderRuns         int          `json:"olderRuns,omitempty"`
+	next              time.Time
+}
+
+type cronJobRun struct {
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	Reason      string `json:"reason,omitempty"`
+	Completions string `json:"completions"`
+	// Duration is empty for runs that haven't started.
+	Duration string `json:"duration,omitempty"`
+	Created  string `json:"created"`
+	age      time.Duration
+}
+
+// cronJobRuns describes the schedule of the CronJob and the newest Jobs it
+// controls.
+func cronJobRuns(cronJob *batchv1.CronJob, jobs []batchv1.Job, limit int, now time.Time) cronJobRunsResult {
+	var runs []batchv1.Job
+	for _, job := range jobs {
+		if owner := metav1.GetControllerOf(&job); owner != nil && owner.UID == cronJob.UID {
+			runs = append(runs, job)
+		}
+	}
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[j].CreationTimestamp.Before(&runs[i].CreationTimestamp)
+	})
+
+	result := cronJobRunsResult{
+		Name:      cronJob.Name,
+		Namespace: cronJob.Namespace,
+		Schedule:  cronJob.Spec.Schedule,
+		Suspended: cronJob.Spec.Suspend != nil && *cronJob.Spec.Suspend,
+		Active:    len(cronJob.Status.Active),
+		Runs:      []cronJobRun{},
+	}
+	if cronJob.Spec.TimeZone != nil {
+		result.TimeZone = *cronJob.Spec.TimeZone
+	}
+	if !result.Suspended {
+		if next, err := kube.GetCronJobNextSchedule(cronJob, now); err != nil {
+			result.NextScheduleError = err.Error()
+		} else {
+			result.next = next
+			result.NextSchedule = next.UTC().Format(time.RFC3339)
+		}
+	}
+	if cronJob.Status.LastScheduleTime != nil {
+		result.LastSchedule = cronJob.Status.LastScheduleTime.UTC().Format(time.RFC3339)
+	}
+	if cronJob.Status.LastSuccessfulTime != nil {
+		result.LastSuccessful = cronJob.Status.LastSuccessfulTime.UTC().Format(time.RFC3339)
+	}
+
+	if len(runs) > limit {
+		result.OlderRuns = len(runs) - limit
+		runs = runs[:limit]
+	}
+	for _, job := range runs {
+		status, reason := kube.GetJobStatus(&job)
+		run := cronJobRun{
+			Name:        job.Name,
+			Status:      status,
+			Reason:      reason,
+			Completions: kube.GetJobCompletions(&job),
+			Created:     job.CreationTimestamp.UTC().Format(time.RFC3339),
+			age:         now.Sub(job.CreationTimestamp.Time),
+		}
+		if d := kube.GetJobDuration(&job, now); d > 0 {
+			run.Duration = duration.HumanDuration(d)
+		}
+		result.Runs = append(result.Runs, run)
+	}
+	return result
+}
+
+// String renders the result as text, with ages relative to now.
+func (r cronJobRunsResult) String(now time.Time) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("CronJob %s/%s\n", r.Namespace, r.Name))
+	sb.WriteString(fmt.Sprintf("Schedule: %s", r.Schedule))
+	if r.TimeZone != "" {
+		sb.WriteString(fmt.Sprintf(" (%s)", r.TimeZone))
+	}
+	sb.WriteString("\n")
+	switch {
+	case r.Suspended:
+		sb.WriteString("Suspended: true\n")
+	case r.NextScheduleError != "":
+		sb.WriteString(fmt.Sprintf("Next schedule: unknown (%s)\n", r.NextScheduleError))
+	default:
+		sb.WriteString(fmt.Sprintf("Next schedule: %s (in %s)\n", r.NextSchedule, duration.HumanDuration(r.next.Sub(now))))
+	}
+	if r.LastSchedule != "" {
+		sb.WriteString(fmt.Sprintf("Last schedule: %s\n", r.LastSchedule))
+	}
+	if r.LastSuccessful != "" {
+		sb.WriteString(fmt.Sprintf("Last successful: %s\n", r.LastSuccessful))
+	}
+	sb.WriteString(fmt.Sprintf("Active: %d\n\n", r.Active))
+
+	if len(r.Runs) == 0 {
+		sb.WriteString("No runs found")
+		return sb.String()
+	}
+
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tSTATUS\tCOMPLETIONS\tDURATION\tAGE\tREASON")
+	for _, run := range r.Runs {
+		jobDuration := run.Duration
+		if jobDuration == "" {
+			jobDuration = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			run.Name, run.Status, run.Completions, jobDuration, duration.HumanDuration(run.age), run.Reason)
+	}
+	_ = w.Flush()
+	if r.OlderRuns > 0 {
+		sb.WriteString(fmt.Sprintf("\n%d older run(s) not shown", r.OlderRuns))
+	}
+	return sb.String()
+}
@@ -0,0 +1,78 @@
+package tool
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestJobFromCronJob(t *testing.T) {
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: strings.Repeat("x", 60), Namespace: "batch", UID: "cj-uid"},
+		Spec: batchv1.CronJobSpec{JobTemplate: batchv1.JobTemplateSpec{
+			ObjectMeta: metav1.ObjectMeta{Labels: map[string]string{"app": "report"}, Annotations: map[string]string{"team": "data"}},
+			Spec:       batchv1.JobSpec{BackoffLimit: new(int32)},
+		}},
+	}
+
+	job := jobFromCronJob(cronJob, "")
+	if len(job.Name) != maxJobNameLength || !strings.HasPrefix(job.Name, "xxx") {
+		t.Errorf("jobFromCronJob() name = %q (%d chars), want a %d character generated name", job.Name, len(job.Name), maxJobNameLength)
+	}
+	if job.Annotations["cronjob.kubernetes.io/instantiate"] != "manual" || job.Annotations["team"] != "data" {
+		t.Errorf("jobFromCronJob() annotations = %v", job.Annotations)
+	}
+	if job.Labels["app"] != "report" || job.Namespace != "batch" {
+		t.Errorf("jobFromCronJob() labels = %v, namespace = %q", job.Labels, job.Namespace)
+	}
+	owner := metav1.GetControllerOf(job)
+	if owner == nil || owner.UID != "cj-uid" || owner.Kind != "CronJob" {
+		t.Errorf("jobFromCronJob() controller = %+v, want the CronJob", owner)
+	}
+
+	if job := jobFromCronJob(cronJob, "manual-run"); job.Name != "manual-run" {
+		t.Errorf("jobFromCronJob() name = %q, want manual-run", job.Name)
+	}
+}
+
+func TestCronJobRuns(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	suspend := true
+	cronJob := &batchv1.CronJob{
+		ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "batch", UID: "cj-uid"},
+		Spec:       batchv1.CronJobSpec{Schedule: "0 * * * *", Suspend: &suspend},
+	}
+	controller := true
+	job := func(name string, age time.Duration, owner types.UID) batchv1.Job {
+		return batchv1.Job{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			OwnerReferences:   []metav1.OwnerReference{{UID: owner, Controller: &controller}},
+		}}
+	}
+	jobs := []batchv1.Job{
+		job("report-1", 3*time.Hour, "cj-uid"),
+		job("report-3", time.Hour, "cj-uid"),
+		job("other", time.Minute, "other-uid"),
+		job("report-2", 2*time.Hour, "cj-uid"),
+	}
+
+	result := cronJobRuns(cronJob, jobs, 2, now)
+	if !result.Suspended || result.NextSchedule != "" || result.OlderRuns != 1 {
+		t.Errorf("cronJobRuns() = %+v, want suspended with 1 older run", result)
+	}
+	var names []string
+	for _, run := range result.Runs {
+		names = append(names, run.Name)
+	}
+	if strings.Join(names, ",") != "report-3,report-2" {
+		t.Errorf("cronJobRuns() runs = %v, want the 2 newest of the CronJob", names)
+	}
+	if text := result.String(now); !strings.Contains(text, "Suspended: true") || !strings.Contains(text, "1 older run(s) not shown") {
+		t.Errorf("String() = %q", text)
+	}
+}
@@ -2,7 +2,6 @@ package tool
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"strings"
 
@@ -11,8 +10,9 @@ import (
 )
 
 func (h *Handler) registerKubectl(m *server.MCPServer) {
-	m.AddTool(mcp.NewTool("kubectl_get",
+	h.addTool(m, mcp.NewTool("kubectl_get",
 		mcp.WithDescription("Execute kubectl get command for any Kubernetes resource type with advanced filtering options"),
+		mcp.WithOutputSchema[KubectlGetResult](),
 		mcp.WithString("resource",
 			mcp.Description("The resource type to get (e.g., pods, deployments, services, nodes, etc.)"),
 			mcp.Required(),
@@ -50,10 +50,18 @@ func (h *Handler) registerKubectl(m *server.MCPServer) {
 		mcp.WithString("jsonpath",
 			mcp.Description("JSONPath expression when output format is 'jsonpath' (e.g., '{.items[*].metadata.name}')"),
 		),
+		mcp.WithArray("fields",
+			mcp.Description("JSONPaths to keep of each object, e.g. ['.metadata.name', '.status.phase', '.spec.containers[*].image'] (implies json output)"),
+			mcp.WithStringItems(),
+		),
+		mcp.WithString("query",
+			mcp.Description("jq expression evaluated server-side over the fetched JSON, returning only its result (implies json output), e.g. '[.items[] | select(.status.phase != \"Running\") | .metadata.name]' or '.items | group_by(.spec.nodeName) | map({node: .[0].spec.nodeName, pods: length})'"),
+		),
 	), mcp.NewTypedToolHandler[KubectlGetArgs](h.kubectlGetHandler()))
 
-	m.AddTool(mcp.NewTool("kubectl_describe",
+	h.addTool(m, mcp.NewTool("kubectl_describe",
 		mcp.WithDescription("Execute kubectl describe command for detailed information about Kubernetes resources"),
+		mcp.WithOutputSchema[KubectlResult](),
 		mcp.WithString("resource",
 			mcp.Description("The resource type to describe (e.g., pod, deployment, service, node, etc.)"),
 			mcp.Required(),
@@ -67,8 +75,9 @@ func (h *Handler) registerKubectl(m *server.MCPServer) {
 		),
 	), mcp.NewTypedToolHandler[KubectlDescribeArgs](h.kubectlDescribeHandler()))
 
-	m.AddTool(mcp.NewTool("kubectl_logs",
+	h.addTool(m, mcp.NewTool("kubectl_logs",
 		mcp.WithDescription("Execute kubectl logs command to get logs from pods"),
+		mcp.WithOutputSchema[KubectlResult](),
 		mcp.WithString("pod_name",
 			mcp.Description("Name of the pod to get logs from"),
 			mcp.Required(),
@@ -103,8 +112,9 @@ func (h *Handler) registerKubectl(m *server.MCPServer) {
 		),
 	), mcp.NewTypedToolHandler[KubectlLogsArgs](h.kubectlLogsHandler()))
 
-	m.AddTool(mcp.NewTool("kubectl_create",
+	h.addTool(m, mcp.NewTool("kubectl_create",
 		mcp.WithDescription("Execute kubectl create command to create Kubernetes resources"),
+		mcp.WithOutputSchema[KubectlResult](),
 		mcp.WithString("filename",
 			mcp.Description("Filename or URL of the resource to create (e.g., deployment.yaml)"),
 		),
@@ -129,8 +139,9 @@ func (h *Handler) registerKubectl(m *server.MCPServer) {
 		),
 	), mcp.NewTypedToolHandler[KubectlCreateArgs](h.kubectlCreateHandler()))
 
-	m.AddTool(mcp.NewTool("kubectl_delete",
+	h.addTool(m, mcp.NewTool("kubectl_delete",
 		mcp.WithDescription("Execute kubectl delete command to delete Kubernetes resources"),
+		mcp.WithOutputSchema[KubectlResult](),
 		mcp.WithString("resource",
 			mcp.Description("Resource type to delete (e.g., pod, deployment, service)"),
 			mcp.Required(),
@@ -164,8 +175,9 @@ func (h *Handler) registerKubectl(m *server.MCPServer) {
 		),
 	), mcp.NewTypedToolHandler[KubectlDeleteArgs](h.kubectlDeleteHandler()))
 
-	m.AddTool(mcp.NewTool("kubectl_apply",
+	h.addTool(m, mcp.NewTool("kubectl_apply",
 		mcp.WithDescription("Execute kubectl apply command to apply configuration to resources"),
+		mcp.WithOutputSchema[KubectlResult](),
 		mcp.WithString("filename",
 			mcp.Description("Filename, directory, or URL of the resource to apply"),
 			mcp.Required(),
@@ -194,8 +206,9 @@ func (h *Handler) registerKubectl(m *server.MCPServer) {
 		),
 	), mcp.NewTypedToolHandler[KubectlApplyArgs](h.kubectlApplyHandler()))
 
-	m.AddTool(mcp.NewTool("kubectl_label",
+	h.addTool(m, mcp.NewTool("kubectl_label",
 		mcp.WithDescription("Execute kubectl label command to add, update, or remove labels on resources"),
+		mcp.WithOutputSchema[KubectlResult](),
 		mcp.WithString("resource",
 			mcp.Description("Resource type to label (e.g., pod, node, deployment)"),
 			mcp.Required(),
@@ -223,8 +236,9 @@ func (h *Handler) registerKubectl(m *server.MCPServer) {
 		),
 	), mcp.NewTypedToolHandler[KubectlLabelArgs](h.kubectlLabelHandler()))
 
-	m.AddTool(mcp.NewTool("kubectl_annotate",
+	h.addTool(m, mcp.NewTool("kubectl_annotate",
 		mcp.WithDescription("Execute kubectl annotate command to add, update, or remove annotations on resources"),
+		mcp.WithOutputSchema[KubectlResult](),
 		mcp.WithString("resource",
 			mcp.Description("Resource type to annotate (e.g., pod, node, deployment)"),
 			mcp.Required(),
@@ -252,31 +266,38 @@ func (h *Handler) registerKubectl(m *server.MCPServer) {
 		),
 	), mcp.NewTypedToolHandler[KubectlAnnotateArgs](h.kubectlAnnotateHandler()))
 
-	m.AddTool(mcp.NewTool("kubectl_generic",
+	h.addTool(m, mcp.NewTool("kubectl_generic",
 		mcp.WithDescription("Execute any kubectl command with custom arguments - use this for kubectl functionality not covered by other specific tools"),
+		mcp.WithOutputSchema[KubectlResult](),
 		mcp.WithString("args",
-			mcp.Description("Complete kubectl command arguments as a space-separated string (e.g., 'get pods --all-namespaces', 'scale deployment nginx --replicas=3', 'port-forward pod/nginx 8080:80', 'exec pod-name -- ls /app')"),
+			mcp.Description("Complete kubectl command arguments, either as a JSON array of strings (e.g., '[\"get\", \"pods\", \"-o\", \"jsonpath={.items[*].metadata.name}\"]') or as a shell-quoted string using POSIX quoting rules (e.g., 'get pods --all-namespaces', 'scale deployment nginx --replicas=3', 'exec pod-name -- sh -c \"ls /app | wc -l\"'). Overriding the identity or cluster (--kubeconfig, --context, --server, --token, --as, ...) and the proxy, config and plugin subcommands are not allowed."),
 			mcp.Required(),
 		),
 		mcp.WithBoolean("parse_json",
-			mcp.Description("Attempt to parse and format JSON output for better readability"),
+			mcp.Description("Parse JSON output, stripping managedFields and last-applied annotations from objects and summarising oversized lists"),
 			mcp.DefaultBool(true),
 		),
+		mcp.WithArray("fields",
+			mcp.Description("JSONPaths to keep of each object in JSON output, e.g. ['.metadata.name', '.status.phase'] (requires parse_json)"),
+			mcp.WithStringItems(),
+		),
 	), mcp.NewTypedToolHandler[KubectlGenericArgs](h.kubectlGenericHandler()))
 }
 
 type KubectlGetArgs struct {
-	Resource      string `json:"resource"`
-	Name          string `json:"name,omitempty"`
-	Namespace     string `json:"namespace,omitempty"`
-	FieldSelector string `json:"field_selector,omitempty"`
-	LabelSelector string `json:"label_selector,omitempty"`
-	Output        string `json:"output"`
-	AllNamespaces bool   `json:"all_namespaces"`
-	ShowLabels    bool   `json:"show_labels"`
-	SortBy        string `json:"sort_by,omitempty"`
-	CustomColumns string `json:"custom_columns,omitempty"`
-	JSONPath      string `json:"jsonpath,omitempty"`
+	Resource      string   `json:"resource"`
+	Name          string   `json:"name,omitempty"`
+	Namespace     string   `json:"namespace,omitempty"`
+	FieldSelector string   `json:"field_selector,omitempty"`
+	LabelSelector string   `json:"label_selector,omitempty"`
+	Output        string   `json:"output"`
+	AllNamespaces bool     `json:"all_namespaces"`
+	ShowLabels    bool     `json:"show_labels"`
+	SortBy        string   `json:"sort_by,omitempty"`
+	CustomColumns string   `json:"custom_columns,omitempty"`
+	JSONPath      string   `json:"jsonpath,omitempty"`
+	Fields        []string `json:"fields,omitempty"`
+	Query         string   `json:"query,omitempty"`
 }
 
 func (h *Handler) kubectlGetHandler() mcp.TypedToolHandlerFunc[KubectlGetArgs] {
@@ -285,6 +306,16 @@ func (h *Handler) kubectlGetHandler() mcp.TypedToolHandlerFunc[KubectlGetArgs] {
 		req mcp.CallToolRequest,
 		args KubectlGetArgs,
 	) (*mcp.CallToolResult, error) {
+		if len(args.Fields) > 0 && args.Query != "" {
+			return mcp.NewToolResultError("fields and query can't be combined; select the fields in the query instead"), nil
+		}
+		if len(args.Fields) > 0 || args.Query != "" {
+			if args.Output != "" && args.Output != "json" {
+				return mcp.NewToolResultError("fields and query can only be used with json output"), nil
+			}
+			args.Output = "json"
+		}
+
 		cmdArgs := []string{"get", args.Resource}
 
 		if args.Name != "" {
@@ -327,26 +358,36 @@ func (h *Handler) kubectlGetHandler() mcp.TypedToolHandlerFunc[KubectlGetArgs] {
 			cmdArgs = append(cmdArgs, "--sort-by", args.SortBy)
 		}
 
-		output, err := h.runKubectl(ctx, cmdArgs...)
+		res, err := h.runKubectl(ctx, cmdArgs...)
 		if err != nil {
-			errorMsg := fmt.Sprintf("kubectl command failed: %v\nCommand: kubectl %s\nOutput: %s",
-				err, strings.Join(cmdArgs, " "), string(output))
-			return mcp.NewToolResultError(errorMsg), nil
+			return kubectlErrorResult("kubectl command failed", res, err), nil
 		}
 
-		response := string(output)
-		if args.Output == "json" && len(output) > 0 {
-			var jsonData interface{}
-			if err := json.Unmarshal(output, &jsonData); err == nil {
-				if formatted, err := json.MarshalIndent(jsonData, "", "  "); err == nil {
-					response = string(formatted)
-				}
+		if args.Query != "" {
+			queryResult, text, err := queryJSON(ctx, args.Query, []byte(res.Stdout))
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to run query", err), nil
 			}
+			res.Stdout = text
+			result := kubectlToolResult(res, text)
+			result.StructuredContent = KubectlGetResult{KubectlResult: res, QueryResult: queryResult}
+			return result, nil
 		}
 
-		fullResponse := fmt.Sprintf("Command executed: kubectl %s\n\n%s", strings.Join(cmdArgs, " "), response)
+		getResult := KubectlGetResult{}
+		if args.Output == "json" {
+			getResult.Items = objectSummaries([]byte(res.Stdout))
+		}
+		response, err := h.shapeKubectlOutput(res.Stdout, args.Output, args.Fields)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to shape output", err), nil
+		}
+		res.Stdout = response
 
-		return mcp.NewToolResultText(fullResponse), nil
+		result := kubectlToolResult(res, response)
+		getResult.KubectlResult = res
+		result.StructuredContent = getResult
+		return result, nil
 	}
 }
 
@@ -367,15 +408,12 @@ func (h *Handler) kubectlDescribeHandler() mcp.TypedToolHandlerFunc[KubectlDescr
 			cmdArgs = append(cmdArgs, "-n", args.Namespace)
 		}
 
-		output, err := h.runKubectl(ctx, cmdArgs...)
+		res, err := h.runKubectl(ctx, cmdArgs...)
 		if err != nil {
-			errorMsg := fmt.Sprintf("kubectl describe failed: %v\nCommand: kubectl %s\nOutput: %s",
-				err, strings.Join(cmdArgs, " "), string(output))
-			return mcp.NewToolResultError(errorMsg), nil
+			return kubectlErrorResult("kubectl describe failed", res, err), nil
 		}
 
-		fullResponse := fmt.Sprintf("Command executed: kubectl %s\n\n%s", strings.Join(cmdArgs, " "), string(output))
-		return mcp.NewToolResultText(fullResponse), nil
+		return kubectlToolResult(res, res.Stdout), nil
 	}
 }
 
@@ -423,15 +461,12 @@ func (h *Handler) kubectlLogsHandler() mcp.TypedToolHandlerFunc[KubectlLogsArgs]
 		if args.Timestamps {
 			cmdArgs = append(cmdArgs, "--timestamps")
 		}
-		output, err := h.runKubectl(ctx, cmdArgs...)
+		res, err := h.runKubectl(ctx, cmdArgs...)
 		if err != nil {
-			errorMsg := fmt.Sprintf("kubectl logs failed: %v\nCommand: kubectl %s\nOutput: %s",
-				err, strings.Join(cmdArgs, " "), string(output))
-			return mcp.NewToolResultError(errorMsg), nil
+			return kubectlErrorResult("kubectl logs failed", res, err), nil
 		}
 
-		fullResponse := fmt.Sprintf("Command executed: kubectl %s\n\n%s", strings.Join(cmdArgs, " "), string(output))
-		return mcp.NewToolResultText(fullResponse), nil
+		return kubectlToolResult(res, res.Stdout), nil
 	}
 }
 
@@ -477,15 +512,12 @@ func (h *Handler) kubectlCreateHandler() mcp.TypedToolHandlerFunc[KubectlCreateA
 			cmdArgs = append(cmdArgs, "-o", args.Output)
 		}
 
-		output, err := h.runKubectl(ctx, cmdArgs...)
+		res, err := h.runKubectl(ctx, cmdArgs...)
 		if err != nil {
-			errorMsg := fmt.Sprintf("kubectl create failed: %v\nCommand: kubectl %s\nOutput: %s",
-				err, strings.Join(cmdArgs, " "), string(output))
-			return mcp.NewToolResultError(errorMsg), nil
+			return kubectlErrorResult("kubectl create failed", res, err), nil
 		}
 
-		fullResponse := fmt.Sprintf("Command executed: kubectl %s\n\n%s", strings.Join(cmdArgs, " "), string(output))
-		return mcp.NewToolResultText(fullResponse), nil
+		return kubectlToolResult(res, res.Stdout), nil
 	}
 }
 
@@ -507,46 +539,49 @@ func (h *Handler) kubectlDeleteHandler() mcp.TypedToolHandlerFunc[KubectlDeleteA
 		req mcp.CallToolRequest,
 		args KubectlDeleteArgs,
 	) (*mcp.CallToolResult, error) {
-		var cmdArgs []string
+		cmdArgs := kubectlDeleteCommand(args)
 
-		if args.Filename != "" {
-			cmdArgs = []string{"delete", "-f", args.Filename}
-		} else {
-			cmdArgs = []string{"delete", args.Resource}
-			if args.Name != "" {
-				cmdArgs = append(cmdArgs, args.Name)
-			}
+		res, err := h.runKubectl(ctx, cmdArgs...)
+		if err != nil {
+			return kubectlErrorResult("kubectl delete failed", res, err), nil
 		}
 
-		if args.Namespace != "" {
-			cmdArgs = append(cmdArgs, "-n", args.Namespace)
-		}
-		if args.LabelSelector != "" {
-			cmdArgs = append(cmdArgs, "-l", args.LabelSelector)
-		}
-		if args.All {
-			cmdArgs = append(cmdArgs, "--all")
-		}
-		if args.Force {
-			cmdArgs = append(cmdArgs, "--force")
-		}
-		if args.GracePeriod > 0 {
-			cmdArgs = append(cmdArgs, "--grace-period", fmt.Sprintf("%d", args.GracePeriod))
-		}
-		if args.IgnoreNotFound {
-			cmdArgs = append(cmdArgs, "--ignore-not-found")
-		}
+		return kubectlToolResult(res, res.Stdout), nil
+	}
+}
 
-		output, err := h.runKubectl(ctx, cmdArgs...)
-		if err != nil {
-			errorMsg := fmt.Sprintf("kubectl delete failed: %v\nCommand: kubectl %s\nOutput: %s",
-				err, strings.Join(cmdArgs, " "), string(output))
-			return mcp.NewToolResultError(errorMsg), nil
+// kubectlDeleteCommand returns the kubectl arguments for a kubectl_delete call.
+func kubectlDeleteCommand(args KubectlDeleteArgs) []string {
+	var cmdArgs []string
+
+	if args.Filename != "" {
+		cmdArgs = []string{"delete", "-f", args.Filename}
+	} else {
+		cmdArgs = []string{"delete", args.Resource}
+		if args.Name != "" {
+			cmdArgs = append(cmdArgs, args.Name)
 		}
+	}
 
-		fullResponse := fmt.Sprintf("Command executed: kubectl %s\n\n%s", strings.Join(cmdArgs, " "), string(output))
-		return mcp.NewToolResultText(fullResponse), nil
+	if args.Namespace != "" {
+		cmdArgs = append(cmdArgs, "-n", args.Namespace)
+	}
+	if args.LabelSelector != "" {
+		cmdArgs = append(cmdArgs, "-l", args.LabelSelector)
+	}
+	if args.All {
+		cmdArgs = append(cmdArgs, "--all")
+	}
+	if args.Force {
+		cmdArgs = append(cmdArgs, "--force")
+	}
+	if args.GracePeriod > 0 {
+		cmdArgs = append(cmdArgs, "--grace-period", fmt.Sprintf("%d", args.GracePeriod))
+	}
+	if args.IgnoreNotFound {
+		cmdArgs = append(cmdArgs, "--ignore-not-found")
 	}
+	return cmdArgs
 }
 
 type KubectlApplyArgs struct {
@@ -565,47 +600,46 @@ func (h *Handler) kubectlApplyHandler() mcp.TypedToolHandlerFunc[KubectlApplyArg
 		req mcp.CallToolRequest,
 		args KubectlApplyArgs,
 	) (*mcp.CallToolResult, error) {
-		cmdArgs := []string{"apply", "-f", args.Filename}
+		cmdArgs := kubectlApplyCommand(args)
 
-		if args.Namespace != "" {
-			cmdArgs = append(cmdArgs, "-n", args.Namespace)
-		}
-		if args.Recursive {
-			cmdArgs = append(cmdArgs, "--recursive")
-		}
-		if args.DryRun {
-			cmdArgs = append(cmdArgs, "--dry-run=client")
-		}
-		if args.Output != "" {
-			cmdArgs = append(cmdArgs, "-o", args.Output)
-		}
-		if args.Force {
-			cmdArgs = append(cmdArgs, "--force")
-		}
-		if !args.Validate {
-			cmdArgs = append(cmdArgs, "--validate=false")
+		res, err := h.runKubectl(ctx, cmdArgs...)
+		if err != nil {
+			return kubectlErrorResult("kubectl apply failed", res, err), nil
 		}
 
-		output, err := h.runKubectl(ctx, cmdArgs...)
+		response, err := h.shapeKubectlOutput(res.Stdout, args.Output, nil)
 		if err != nil {
-			errorMsg := fmt.Sprintf("kubectl apply failed: %v\nCommand: kubectl %s\nOutput: %s",
-				err, strings.Join(cmdArgs, " "), string(output))
-			return mcp.NewToolResultError(errorMsg), nil
+			return mcp.NewToolResultErrorFromErr("failed to shape output", err), nil
 		}
+		res.Stdout = response
 
-		response := string(output)
-		if args.Output == "json" && len(output) > 0 {
-			var jsonData interface{}
-			if err := json.Unmarshal(output, &jsonData); err == nil {
-				if formatted, err := json.MarshalIndent(jsonData, "", "  "); err == nil {
-					response = string(formatted)
-				}
-			}
-		}
+		return kubectlToolResult(res, response), nil
+	}
+}
+
+// kubectlApplyCommand returns the kubectl arguments for a kubectl_apply call.
+func kubectlApplyCommand(args KubectlApplyArgs) []string {
+	cmdArgs := []string{"apply", "-f", args.Filename}
 
-		fullResponse := fmt.Sprintf("Command executed: kubectl %s\n\n%s", strings.Join(cmdArgs, " "), response)
-		return mcp.NewToolResultText(fullResponse), nil
+	if args.Namespace != "" {
+		cmdArgs = append(cmdArgs, "-n", args.Namespace)
 	}
+	if args.Recursive {
+		cmdArgs = append(cmdArgs, "--recursive")
+	}
+	if args.DryRun {
+		cmdArgs = append(cmdArgs, "--dry-run=client")
+	}
+	if args.Output != "" {
+		cmdArgs = append(cmdArgs, "-o", args.Output)
+	}
+	if args.Force {
+		cmdArgs = append(cmdArgs, "--force")
+	}
+	if !args.Validate {
+		cmdArgs = append(cmdArgs, "--validate=false")
+	}
+	return cmdArgs
 }
 
 type KubectlLabelArgs struct {
@@ -646,15 +680,12 @@ func (h *Handler) kubectlLabelHandler() mcp.TypedToolHandlerFunc[KubectlLabelArg
 			cmdArgs = append(cmdArgs, "--all")
 		}
 
-		output, err := h.runKubectl(ctx, cmdArgs...)
+		res, err := h.runKubectl(ctx, cmdArgs...)
 		if err != nil {
-			errorMsg := fmt.Sprintf("kubectl label failed: %v\nCommand: kubectl %s\nOutput: %s",
-				err, strings.Join(cmdArgs, " "), string(output))
-			return mcp.NewToolResultError(errorMsg), nil
+			return kubectlErrorResult("kubectl label failed", res, err), nil
 		}
 
-		fullResponse := fmt.Sprintf("Command executed: kubectl %s\n\n%s", strings.Join(cmdArgs, " "), string(output))
-		return mcp.NewToolResultText(fullResponse), nil
+		return kubectlToolResult(res, res.Stdout), nil
 	}
 }
 
@@ -696,21 +727,19 @@ func (h *Handler) kubectlAnnotateHandler() mcp.TypedToolHandlerFunc[KubectlAnnot
 			cmdArgs = append(cmdArgs, "--all")
 		}
 
-		output, err := h.runKubectl(ctx, cmdArgs...)
+		res, err := h.runKubectl(ctx, cmdArgs...)
 		if err != nil {
-			errorMsg := fmt.Sprintf("kubectl annotate failed: %v\nCommand: kubectl %s\nOutput: %s",
-				err, strings.Join(cmdArgs, " "), string(output))
-			return mcp.NewToolResultError(errorMsg), nil
+			return kubectlErrorResult("kubectl annotate failed", res, err), nil
 		}
 
-		fullResponse := fmt.Sprintf("Command executed: kubectl %s\n\n%s", strings.Join(cmdArgs, " "), string(output))
-		return mcp.NewToolResultText(fullResponse), nil
+		return kubectlToolResult(res, res.Stdout), nil
 	}
 }
 
 type KubectlGenericArgs struct {
-	Args      string `json:"args"`
-	ParseJSON bool   `json:"parse_json"`
+	Args      KubectlArgs `json:"args"`
+	ParseJSON bool        `json:"parse_json"`
+	Fields    []string    `json:"fields,omitempty"`
 }
 
 func (h *Handler) kubectlGenericHandler() mcp.TypedToolHandlerFunc[KubectlGenericArgs] {
@@ -719,42 +748,32 @@ func (h *Handler) kubectlGenericHandler() mcp.TypedToolHandlerFunc[KubectlGeneri
 		req mcp.CallToolRequest,
 		args KubectlGenericArgs,
 	) (*mcp.CallToolResult, error) {
-		if args.Args == "" {
-			return mcp.NewToolResultError("args parameter is required"), nil
+		cmdArgs := []string(args.Args)
+		if err := validateKubectlArgs(cmdArgs); err != nil {
+			return mcp.NewToolResultErrorFromErr("invalid kubectl arguments", err), nil
 		}
 
-		// Split the args string into individual arguments
-		cmdArgs := strings.Fields(args.Args)
-
-		if len(cmdArgs) == 0 {
-			return mcp.NewToolResultError("at least one argument is required"), nil
+		res, err := h.runKubectl(ctx, cmdArgs...)
+		if err != nil {
+			return kubectlErrorResult("kubectl command failed", res, err), nil
 		}
 
-		output, err := h.runKubectl(ctx, cmdArgs...)
-		if err != nil {
-			errorMsg := fmt.Sprintf("kubectl command failed: %v\nCommand: kubectl %s\nOutput: %s",
-				err, strings.Join(cmdArgs, " "), string(output))
-			return mcp.NewToolResultError(errorMsg), nil
-		}
-
-		response := string(output)
-
-		// Attempt to format JSON output if requested and if the output appears to be JSON
-		if args.ParseJSON && len(output) > 0 {
-			// Check if output looks like JSON (starts with { or [)
-			trimmed := strings.TrimSpace(string(output))
-			if (strings.HasPrefix(trimmed, "{") && strings.HasSuffix(trimmed, "}")) ||
-				(strings.HasPrefix(trimmed, "[") && strings.HasSuffix(trimmed, "]")) {
-				var jsonData interface{}
-				if err := json.Unmarshal(output, &jsonData); err == nil {
-					if formatted, err := json.MarshalIndent(jsonData, "", "  "); err == nil {
-						response = string(formatted)
-					}
+		response := res.Stdout
+
+		// Shape JSON objects if requested; other output, like the arrays a
+		// jsonpath query prints, is returned unchanged.
+		if args.ParseJSON && len(res.Stdout) > 0 {
+			trimmed := strings.TrimSpace(res.Stdout)
+			if strings.HasPrefix(trimmed, "{") && strings.HasSuffix(trimmed, "}") {
+				shaped, err := h.shapeKubectlOutput(res.Stdout, "json", args.Fields)
+				if err != nil {
+					return mcp.NewToolResultErrorFromErr("failed to shape output", err), nil
 				}
+				response = shaped
 			}
+			res.Stdout = response
 		}
 
-		fullResponse := fmt.Sprintf("Command executed: kubectl %s\n\n%s", strings.Join(cmdArgs, " "), response)
-		return mcp.NewToolResultText(fullResponse), nil
+		return kubectlToolResult(res, response), nil
 	}
 }
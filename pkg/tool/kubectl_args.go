@@ -0,0 +1,429 @@
+package tool
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// KubectlArgs holds kubectl arguments supplied either as a JSON array of
+// strings or as a single shell-quoted string following POSIX quoting rules.
+type KubectlArgs []string
+
+func (a *KubectlArgs) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*a = list
+		return nil
+	}
+
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return errors.New("args must be a JSON array of strings or a shell-quoted string")
+	}
+	parsed, err := parseKubectlArgs(raw)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// parseKubectlArgs parses a string that is either a JSON array of strings or
+// a shell-quoted argument string.
+func parseKubectlArgs(s string) ([]string, error) {
+	trimmed := strings.TrimSpace(s)
+	if strings.HasPrefix(trimmed, "[") {
+		var list []string
+		if err := json.Unmarshal([]byte(trimmed), &list); err != nil {
+			return nil, fmt.Errorf("args looks like a JSON array but could not be parsed: %w", err)
+		}
+		return list, nil
+	}
+	return splitShellWords(s)
+}
+
+// splitShellWords splits a string into words using POSIX shell quoting rules:
+// single quotes preserve everything literally, double quotes allow backslash
+// escapes of $, `, ", \ and newline, and an unquoted backslash escapes the next
+// character. No expansion of variables, globs or substitutions is performed.
+func splitShellWords(s string) ([]string, error) {
+	var (
+		words   []string
+		current strings.Builder
+		inWord  bool
+	)
+
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '\'':
+			inWord = true
+			end := i + 1
+			for end < len(runes) && runes[end] != '\'' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.New("unterminated single quote in args")
+			}
+			current.WriteString(string(runes[i+1 : end]))
+			i = end
+		case r == '"':
+			inWord = true
+			i++
+			for ; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune("$`\"\\\n", runes[i+1]) {
+					i++
+					if runes[i] == '\n' {
+						continue
+					}
+				}
+				current.WriteRune(runes[i])
+			}
+			if i == len(runes) {
+				return nil, errors.New("unterminated double quote in args")
+			}
+		case r == '\\':
+			if i+1 == len(runes) {
+				return nil, errors.New("trailing backslash in args")
+			}
+			i++
+			if runes[i] == '\n' {
+				continue
+			}
+			inWord = true
+			current.WriteRune(runes[i])
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, current.String())
+				current.Reset()
+				inWord = false
+			}
+		default:
+			inWord = true
+			current.WriteRune(r)
+		}
+	}
+	if inWord {
+		words = append(words, current.String())
+	}
+
+	return words, nil
+}
+
+// deniedKubectlFlags are flags that would let a caller escape the identity and
+// cluster the server was configured with.
+var deniedKubectlFlags = []string{
+	"--kubeconfig",
+	"--context",
+	"--cluster",
+	"--server",
+	"-s",
+	"--token",
+	"--user",
+	"--username",
+	"--password",
+	"--client-certificate",
+	"--client-key",
+	"--as",
+	"--as-group",
+	"--as-uid",
+}
+
+// deniedKubectlCommands are subcommands that are never allowed through kubectl_generic.
+var deniedKubectlCommands = map[string]string{
+	"proxy":  "it exposes the API server outside of the configured identity",
+	"config": "it reads or modifies the kubeconfig",
+	"plugin": "it manages kubectl plugins",
+}
+
+// allowedKubectlCommands are the built-in kubectl subcommands. Anything else
+// would be resolved by kubectl as a plugin (kubectl-<name> in PATH).
+var allowedKubectlCommands = map[string]bool{
+	"alpha": true, "annotate": true, "api-resources": true, "api-versions": true,
+	"apply": true, "attach": true, "auth": true, "autoscale": true,
+	"certificate": true, "cluster-info": true, "cordon": true, "cp": true,
+	"create": true, "debug": true, "delete": true, "describe": true,
+	"diff": true, "drain": true, "events": true, "exec": true,
+	"explain": true, "expose": true, "get": true, "kustomize": true,
+	"label": true, "logs": true, "patch": true, "port-forward": true,
+	"replace": true, "rollout": true, "run": true, "scale": true,
+	"set": true, "taint": true, "top": true, "uncordon": true,
+	"version": true, "wait": true,
+}
+
+// kubectlValueFlags are global flags that take a separate value, used to
+// locate the subcommand in the argument list.
+var kubectlValueFlags = map[string]bool{
+	"-n": true, "--namespace": true, "--request-timeout": true, "--cache-dir": true,
+	"--certificate-authority": true, "--tls-server-name": true, "-v": true, "--v": true,
+	"--log-file": true, "--profile": true, "--profile-output": true,
+}
+
+// validateKubectlArgs checks the arguments against the kubectl_generic rule set
+// and reports the first rejected token.
+func validateKubectlArgs(args []string) error {
+	if len(args) == 0 {
+		return errors.New("at least one argument is required")
+	}
+
+	command := ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			// Everything after -- belongs to the command run in the container.
+			break
+		}
+
+		for _, flag := range deniedKubectlFlags {
+			if arg == flag || strings.HasPrefix(arg, flag+"=") || (flag == "-s" && strings.HasPrefix(arg, "-s")) {
+				return fmt.Errorf("argument %d %q rejected: %s overrides the configured identity or cluster", i+1, arg, flag)
+			}
+		}
+
+		if command != "" || strings.HasPrefix(arg, "-") {
+			if kubectlValueFlags[arg] {
+				i++
+			}
+			continue
+		}
+
+		command = arg
+		if reason, denied := deniedKubectlCommands[command]; denied {
+			return fmt.Errorf("argument %d %q rejected: the %s subcommand is not allowed because %s", i+1, arg, command, reason)
+		}
+		if !allowedKubectlCommands[command] {
+			return fmt.Errorf("argument %d %q rejected: not a built-in kubectl subcommand (plugins are not allowed)", i+1, arg)
+		}
+	}
+
+	if command == "" {
+		return errors.New("no kubectl subcommand found in args")
+	}
+	return nil
+}
+
+// shellJoin joins arguments into a string that splitShellWords parses back into
+// the same arguments, quoting only where needed.
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\n'\"\\$`*?[]{}()<>|&;#~") {
+			quoted[i] = arg
+			continue
+		}
+		quoted[i] = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+	}
+	return strings.Join(quoted, " ")
+}
+
+// kubectlPodCommands are subcommands whose first argument is a pod.
+var kubectlPodCommands = map[string]bool{
+	"exec": true, "logs": true, "attach": true, "port-forward": true, "cp": true,
+}
+
+// kubectlNestedCommands are subcommands whose first argument is a subcommand
+// of their own, as in kubectl rollout restart.
+var kubectlNestedCommands = map[string]bool{
+	"rollout": true, "set": true, "top": true, "auth": true, "certificate": true,
+}
+
+// kubectlShortValueFlags are the single-letter flags that take a value, which
+// may be attached as in -nprod.
+var kubectlShortValueFlags = map[string]bool{
+	"n": true, "l": true, "o": true, "f": true, "k": true, "c": true, "p": true,
+	"L": true, "e": true, "v": true,
+}
+
+// kubectlShortBoolFlags are the single-letter flags that take no value and so
+// can be combined as in -it.
+var kubectlShortBoolFlags = map[string]bool{
+	"A": true, "R": true, "w": true, "i": true, "t": true, "q": true, "h": true,
+}
+
+// kubectlCommandShortBoolFlags are the single-letter flags that take no value
+// for some subcommands only: -f follows and -p shows the previous container's
+// logs.
+var kubectlCommandShortBoolFlags = map[string]map[string]bool{
+	"logs": {"f": true, "p": true},
+}
+
+// kubectlLongValueFlags are the subcommand flags that take a separate value.
+// Together with kubectlValueFlags and kubectlLongBoolFlags they let
+// kubectlCommand tell flag values from positional arguments.
+var kubectlLongValueFlags = map[string]bool{
+	"selector": true, "field-selector": true, "output": true, "filename": true,
+	"kustomize": true, "container": true, "grace-period": true, "timeout": true,
+	"replicas": true, "current-replicas": true, "resource-version": true,
+	"patch": true, "patch-file": true, "type": true, "for": true, "image": true,
+	"since": true, "since-time": true, "tail": true, "limit-bytes": true,
+	"sort-by": true, "template": true, "label-columns": true, "chunk-size": true,
+	"field-manager": true, "subresource": true, "pod-running-timeout": true,
+	"max-log-requests": true, "port": true, "target-port": true, "protocol": true,
+	"name": true, "env": true, "to-revision": true, "revision": true, "min": true,
+	"max": true, "cpu-percent": true, "delete-emptydir-data-timeout": true,
+	"pod-selector": true, "skip-wait-for-delete-timeout": true, "address": true,
+	"target": true, "restart": true, "overrides": true,
+	"from": true, "from-file": true, "from-literal": true, "from-env-file": true,
+	"docker-server": true, "docker-username": true, "docker-password": true,
+	"docker-email": true, "cert": true, "key": true, "schedule": true,
+	"api-version": true, "api-group": true, "verbs": true, "prune-allowlist": true,
+}
+
+// kubectlLongBoolFlags are the subcommand flags that take no separate value.
+// Some, like --dry-run and --cascade, take an optional value after "=".
+var kubectlLongBoolFlags = map[string]bool{
+	"all": true, "all-namespaces": true, "force": true, "now": true, "wait": true,
+	"ignore-not-found": true, "overwrite": true, "recursive": true, "watch": true,
+	"watch-only": true, "show-labels": true, "no-headers": true, "stdin": true,
+	"tty": true, "follow": true, "previous": true, "timestamps": true,
+	"dry-run": true, "cascade": true, "validate": true, "server-side": true,
+	"force-conflicts": true, "prune": true, "quiet": true, "local": true,
+	"record": true, "ignore-daemonsets": true, "delete-emptydir-data": true,
+	"disable-eviction": true, "rm": true, "attach": true, "leave-stdin-open": true,
+	"all-containers": true, "prefix": true, "ignore-errors": true, "insecure-skip-tls-verify-backend": true,
+	"show-kind": true, "list": true, "show-managed-fields": true, "allow-missing-template-keys": true,
+	"server-print": true, "no-preserve": true, "keep-annotations": true, "show-events": true,
+}
+
+// kubectlInvocation describes kubectl arguments by their subcommand, the
+// resource type and namespace it acts on and the flags given.
+type kubectlInvocation struct {
+	command string
+	// subcommand is set for kubectlNestedCommands.
+	subcommand string
+	resource   string
+	namespace  string
+	// allNamespaces is set by -A or --all-namespaces.
+	allNamespaces bool
+	// flags are keyed without dashes; flags without a value are "true".
+	flags map[string]string
+	// unknownResource and unknownNamespace report that the resource or
+	// namespace can't be told from the arguments, e.g. because an unknown flag
+	// may take the next argument as its value or the resources come from a
+	// file.
+	unknownResource  bool
+	unknownNamespace bool
+}
+
+// kubectlCommand describes the kubectl arguments. It errs on the side of
+// reporting the resource or namespace as unknown rather than guessing.
+func kubectlCommand(args []string) kubectlInvocation {
+	inv := kubectlInvocation{flags: map[string]string{}}
+	var namespaces []string
+	setFlag := func(name, value string) {
+		inv.flags[name] = value
+		switch name {
+		case "n", "namespace":
+			namespaces = append(namespaces, value)
+		case "A", "all-namespaces":
+			inv.allNamespaces = value != "false"
+		}
+	}
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+
+		switch {
+		case strings.HasPrefix(arg, "--"):
+			name, value, found := strings.Cut(arg[2:], "=")
+			switch {
+			case found:
+			case kubectlLongBoolFlags[name]:
+				value = "true"
+			case kubectlValueFlags[arg] || kubectlLongValueFlags[name]:
+				if i+1 < len(args) {
+					i++
+					value = args[i]
+				}
+			default:
+				// An unknown flag may or may not take the next argument.
+				value = "true"
+				if i+1 < len(args) {
+					if strings.HasPrefix(args[i+1], "-") {
+						inv.unknownNamespace = true
+					} else if inv.command == "" || inv.resource == "" {
+						inv.unknownResource = true
+					}
+				}
+			}
+			setFlag(name, value)
+
+		case strings.HasPrefix(arg, "-") && len(arg) > 1:
+			// Short flags can be combined, as in -it, and take their value
+			// attached, as in -nprod or -n=prod, or as the next argument.
+			letters := arg[1:]
+			for len(letters) > 0 {
+				name := letters[:1]
+				letters = letters[1:]
+				if kubectlShortBoolFlags[name] || kubectlCommandShortBoolFlags[inv.command][name] {
+					setFlag(name, "true")
+					continue
+				}
+				if !kubectlShortValueFlags[name] {
+					setFlag(name, "true")
+					inv.unknownNamespace = true
+					if inv.command == "" || inv.resource == "" {
+						inv.unknownResource = true
+					}
+					break
+				}
+				value := strings.TrimPrefix(letters, "=")
+				if letters == "" && i+1 < len(args) {
+					i++
+					value = args[i]
+				}
+				setFlag(name, value)
+				break
+			}
+
+		case inv.command == "":
+			inv.command = arg
+			if kubectlPodCommands[arg] {
+				inv.resource = "pods"
+			}
+
+		case kubectlNestedCommands[inv.command] && inv.subcommand == "":
+			inv.subcommand = arg
+
+		default:
+			resource, _, isTyped := strings.Cut(arg, "/")
+			switch {
+			case inv.resource == "":
+				inv.resource = resource
+			case isTyped && resource != inv.resource:
+				// kubectl delete pod/a secret/b acts on several types.
+				inv.unknownResource = true
+			}
+		}
+	}
+
+	if strings.Contains(inv.resource, ",") {
+		inv.unknownResource = true
+	}
+	for _, name := range []string{"f", "filename", "k", "kustomize"} {
+		if _, ok := inv.flags[name]; ok && !kubectlPodCommands[inv.command] {
+			inv.unknownResource = true
+		}
+	}
+	if len(namespaces) > 0 {
+		inv.namespace = namespaces[len(namespaces)-1]
+		for _, ns := range namespaces {
+			if ns != inv.namespace {
+				inv.unknownNamespace = true
+			}
+		}
+	}
+	if inv.unknownResource {
+		inv.resource = ""
+	}
+	if inv.unknownNamespace {
+		inv.namespace = ""
+	}
+	return inv
+}
@@ -0,0 +1,112 @@
+package tool
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitShellWords(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{"get pods --all-namespaces", []string{"get", "pods", "--all-namespaces"}, false},
+		{"  get   pods  ", []string{"get", "pods"}, false},
+		{`get pods -o jsonpath='{.items[*].metadata.name}'`, []string{"get", "pods", "-o", "jsonpath={.items[*].metadata.name}"}, false},
+		{`exec nginx -- sh -c "ls /app | wc -l"`, []string{"exec", "nginx", "--", "sh", "-c", "ls /app | wc -l"}, false},
+		{`patch deploy x -p '{"spec": {"replicas": 3}}'`, []string{"patch", "deploy", "x", "-p", `{"spec": {"replicas": 3}}`}, false},
+		{`echo "a \"quoted\" \$word"`, []string{"echo", `a "quoted" $word`}, false},
+		{`echo "keep \n as is"`, []string{"echo", `keep \n as is`}, false},
+		{`echo a\ b`, []string{"echo", "a b"}, false},
+		{`echo '' ""`, []string{"echo", "", ""}, false},
+		{`echo 'it'\''s'`, []string{"echo", "it's"}, false},
+		{`echo 'unterminated`, nil, true},
+		{`echo "unterminated`, nil, true},
+		{`echo trailing\`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := splitShellWords(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("splitShellWords() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitShellWords() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKubectlArgsUnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		{`["get", "pods", "-o", "jsonpath={.items[*].metadata.name}"]`, []string{"get", "pods", "-o", "jsonpath={.items[*].metadata.name}"}},
+		{`"get pods -l 'app in (a, b)'"`, []string{"get", "pods", "-l", "app in (a, b)"}},
+		{`"[\"get\", \"pods\"]"`, []string{"get", "pods"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var got KubectlArgs
+			if err := json.Unmarshal([]byte(tt.input), &got); err != nil {
+				t.Fatalf("UnmarshalJSON() error = %v", err)
+			}
+			if !reflect.DeepEqual([]string(got), tt.want) {
+				t.Errorf("UnmarshalJSON() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateKubectlArgs(t *testing.T) {
+	tests := []struct {
+		args       []string
+		wantReject string
+	}{
+		{[]string{"get", "pods", "-n", "default"}, ""},
+		{[]string{"-n", "default", "get", "pods"}, ""},
+		{[]string{"exec", "nginx", "--", "kubectl", "--token=abc", "proxy"}, ""},
+		{[]string{"get", "pods", "--kubeconfig", "/tmp/other"}, `"--kubeconfig"`},
+		{[]string{"get", "pods", "--token=abc"}, `"--token=abc"`},
+		{[]string{"--server=https://other:6443", "get", "pods"}, `"--server=https://other:6443"`},
+		{[]string{"get", "pods", "-shttps://other:6443"}, `"-shttps://other:6443"`},
+		{[]string{"get", "secrets", "--as", "system:admin"}, `"--as"`},
+		{[]string{"--user", "other-admin", "get", "secrets"}, `"--user"`},
+		{[]string{"get", "secrets", "--user=other-admin"}, `"--user=other-admin"`},
+		{[]string{"get", "secrets", "--as-group=system:masters"}, `"--as-group=system:masters"`},
+		{[]string{"proxy", "--port=8001"}, `"proxy"`},
+		{[]string{"-n", "default", "config", "view"}, `"config"`},
+		{[]string{"ctx", "prod"}, `"ctx"`},
+		{[]string{"--v=6"}, "no kubectl subcommand"},
+		{nil, "at least one argument"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			err := validateKubectlArgs(tt.args)
+			if tt.wantReject == "" {
+				if err != nil {
+					t.Errorf("validateKubectlArgs() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantReject) {
+				t.Errorf("validateKubectlArgs() error = %v, want mention of %s", err, tt.wantReject)
+			}
+		})
+	}
+}
+
+func TestShellJoin(t *testing.T) {
+	args := []string{"exec", "nginx", "--", "sh", "-c", "echo 'hi' && ls", ""}
+	got, err := splitShellWords(shellJoin(args))
+	if err != nil {
+		t.Fatalf("splitShellWords() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, args) {
+		t.Errorf("shellJoin() round trip got = %q, want %q", got, args)
+	}
+}
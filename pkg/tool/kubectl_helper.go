@@ -1,15 +1,128 @@
 package tool
 
 import (
+	"bytes"
 	"context"
+	"errors"
+	"fmt"
 	"os/exec"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
-func (h *Handler) runKubectl(ctx context.Context, args ...string) ([]byte, error) {
+// KubectlResult is the structured output of the kubectl tools.
+type KubectlResult struct {
+	Command  string `json:"command"`
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exitCode"`
+}
+
+// runKubectl runs kubectl with the args. The returned error is non-nil when
+// kubectl couldn't be started or exited non-zero; the result is always filled
+// in, with an exit code of -1 when kubectl didn't run.
+func (h *Handler) runKubectl(ctx context.Context, args ...string) (KubectlResult, error) {
+	result := KubectlResult{Command: "kubectl " + shellJoin(args)}
+	release, err := h.acquireKubectl(ctx)
+	if err != nil {
+		result.ExitCode = -1
+		return result, err
+	}
+	defer release()
+
 	if h.kubeconfigPath != "" {
 		args = append([]string{"--kubeconfig", h.kubeconfigPath}, args...)
 	}
 
+	var stdout, stderr bytes.Buffer
 	cmd := exec.CommandContext(ctx, h.kubectlPath, args...)
-	return cmd.CombinedOutput()
+	stopKill := setKubectlCancel(cmd)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err = cmd.Run()
+	stopKill()
+
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.ExitCode()
+	case err != nil:
+		result.ExitCode = -1
+	}
+	return result, err
+}
+
+// kubectlToolResult returns the result of a successful kubectl run with the
+// response (usually the, possibly reformatted, stdout) as text.
+func kubectlToolResult(result KubectlResult, response string) *mcp.CallToolResult {
+	text := fmt.Sprintf("Command executed: %s\n\n%s", result.Command, response)
+	if result.Stderr != "" {
+		text += "\n" + result.Stderr
+	}
+	return mcp.NewToolResultStructured(result, text)
+}
+
+// kubectlErrorResult returns the result of a failed kubectl run.
+func kubectlErrorResult(message string, result KubectlResult, err error) *mcp.CallToolResult {
+	text := fmt.Sprintf("%s: %v\nCommand: %s\nOutput: %s%s", message, err, result.Command, result.Stdout, result.Stderr)
+	toolResult := mcp.NewToolResultStructured(result, text)
+	toolResult.IsError = true
+	return toolResult
+}
+
+// KubectlGetResult is the structured output of kubectl_get. Items is only set
+// for JSON output; MoreItems counts the items left out to limit the size.
+// QueryResult holds the result of the query, if one was given.
+type KubectlGetResult struct {
+	KubectlResult
+	Items       []ObjectSummary `json:"items,omitempty"`
+	MoreItems   int             `json:"moreItems,omitempty"`
+	QueryResult interface{}     `json:"queryResult,omitempty"`
+}
+
+// ObjectSummary holds the identifying fields of an object returned by kubectl.
+type ObjectSummary struct {
+	APIVersion        string            `json:"apiVersion"`
+	Kind              string            `json:"kind"`
+	Name              string            `json:"name"`
+	Namespace         string            `json:"namespace,omitempty"`
+	Labels            map[string]string `json:"labels,omitempty"`
+	CreationTimestamp string            `json:"creationTimestamp,omitempty"`
+}
+
+// objectSummaries summarises the object or list of objects in kubectl's JSON
+// output. It returns nil when the output isn't a Kubernetes object.
+func objectSummaries(data []byte) []ObjectSummary {
+	obj, _, err := unstructured.UnstructuredJSONScheme.Decode(data, nil, nil)
+	if err != nil {
+		return nil
+	}
+
+	var objects []unstructured.Unstructured
+	switch o := obj.(type) {
+	case *unstructured.UnstructuredList:
+		objects = o.Items
+	case *unstructured.Unstructured:
+		objects = []unstructured.Unstructured{*o}
+	}
+
+	summaries := make([]ObjectSummary, 0, len(objects))
+	for _, o := range objects {
+		summary := ObjectSummary{
+			APIVersion: o.GetAPIVersion(),
+			Kind:       o.GetKind(),
+			Name:       o.GetName(),
+			Namespace:  o.GetNamespace(),
+			Labels:     o.GetLabels(),
+		}
+		if ts := o.GetCreationTimestamp(); !ts.IsZero() {
+			summary.CreationTimestamp = ts.UTC().Format(time.RFC3339)
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
 }
@@ -0,0 +1,67 @@
+package tool
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestObjectSummaries(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want []ObjectSummary
+	}{
+		{
+			name: "list",
+			data: `{"apiVersion":"v1","kind":"List","items":[
+				{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web","namespace":"default","labels":{"app":"web"},"creationTimestamp":"2024-01-02T03:04:05Z"}},
+				{"apiVersion":"v1","kind":"Node","metadata":{"name":"node-1"}}]}`,
+			want: []ObjectSummary{
+				{APIVersion: "v1", Kind: "Pod", Name: "web", Namespace: "default", Labels: map[string]string{"app": "web"}, CreationTimestamp: "2024-01-02T03:04:05Z"},
+				{APIVersion: "v1", Kind: "Node", Name: "node-1"},
+			},
+		},
+		{
+			name: "single object",
+			data: `{"apiVersion":"apps/v1","kind":"Deployment","metadata":{"name":"web","namespace":"default"}}`,
+			want: []ObjectSummary{{APIVersion: "apps/v1", Kind: "Deployment", Name: "web", Namespace: "default"}},
+		},
+		{
+			name: "not an object",
+			data: `NAME   READY`,
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := objectSummaries([]byte(tt.data)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("objectSummaries() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestKubectlGetOutputSchema(t *testing.T) {
+	tool := mcp.NewTool("kubectl_get", mcp.WithOutputSchema[KubectlGetResult]())
+	data, err := json.Marshal(tool)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded struct {
+		OutputSchema struct {
+			Type       string                     `json:"type"`
+			Properties map[string]json.RawMessage `json:"properties"`
+		} `json:"outputSchema"`
+	}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	for _, field := range []string{"command", "stdout", "stderr", "exitCode", "items"} {
+		if _, ok := decoded.OutputSchema.Properties[field]; !ok {
+			t.Errorf("output schema is missing %q: %s", field, data)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+//go:build !windows
+
+package tool
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// setKubectlCancel makes a cancelled kubectl command, and any credential
+// plugin it started, first get SIGTERM and then SIGKILL if it's still running
+// after kubectlKillDelay. kubectl runs in its own process group so the
+// signals reach its children too. The returned function must be called once
+// the command has been waited for; it stops the pending SIGKILL, as the
+// process group ID may be reused by then.
+func setKubectlCancel(cmd *exec.Cmd) func() {
+	var kill *time.Timer
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		pgid := cmd.Process.Pid
+		kill = time.AfterFunc(kubectlKillDelay, func() {
+			_ = syscall.Kill(-pgid, syscall.SIGKILL)
+		})
+		return syscall.Kill(-pgid, syscall.SIGTERM)
+	}
+	cmd.WaitDelay = kubectlKillDelay + time.Second
+	// Wait returns only after Cancel did, so kill is set by then if it ever is.
+	return func() {
+		if kill != nil {
+			kill.Stop()
+		}
+	}
+}
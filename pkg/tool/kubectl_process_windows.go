@@ -0,0 +1,17 @@
+//go:build windows
+
+package tool
+
+import (
+	"os/exec"
+)
+
+// setKubectlCancel kills a cancelled kubectl command. Windows has no SIGTERM,
+// so the process is killed right away and there is nothing to stop.
+func setKubectlCancel(cmd *exec.Cmd) func() {
+	cmd.Cancel = func() error {
+		return cmd.Process.Kill()
+	}
+	cmd.WaitDelay = kubectlKillDelay
+	return func() {}
+}
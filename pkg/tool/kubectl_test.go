@@ -0,0 +1,68 @@
+package tool
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestKubectlGenericOutput(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell script as kubectl")
+	}
+	tests := []struct {
+		name      string
+		stdout    string
+		parseJSON bool
+		want      string
+	}{
+		{
+			name:      "array is returned unchanged",
+			stdout:    `["web","db"]`,
+			parseJSON: true,
+			want:      `["web","db"]`,
+		},
+		{
+			name:      "object is shaped",
+			stdout:    `{"kind":"Pod","metadata":{"name":"web","managedFields":[{"manager":"kubectl"}]}}`,
+			parseJSON: true,
+			want:      "web",
+		},
+		{
+			name:   "unparsed object is returned unchanged",
+			stdout: `{"kind":"Pod","metadata":{"name":"web","managedFields":[{"manager":"kubectl"}]}}`,
+			want:   `"managedFields":[{"manager":"kubectl"}]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubectl := filepath.Join(t.TempDir(), "kubectl")
+			script := "#!/bin/sh\ncat <<'EOF'\n" + tt.stdout + "\nEOF\n"
+			if err := os.WriteFile(kubectl, []byte(script), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			h := &Handler{kubectlPath: kubectl}
+
+			req := mcp.CallToolRequest{}
+			result, err := h.kubectlGenericHandler()(context.Background(), req, KubectlGenericArgs{
+				Args:      KubectlArgs{"get", "pods", "-o", "json"},
+				ParseJSON: tt.parseJSON,
+			})
+			if err != nil || result.IsError {
+				t.Fatalf("handler() = %v, %v", resultText(result), err)
+			}
+			text := resultText(result)
+			if !strings.Contains(text, tt.want) {
+				t.Errorf("output = %q, want it to contain %q", text, tt.want)
+			}
+			if tt.parseJSON && strings.Contains(text, "managedFields") {
+				t.Errorf("output = %q, want managedFields stripped", text)
+			}
+		})
+	}
+}
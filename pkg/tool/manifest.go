@@ -0,0 +1,156 @@
+package tool
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+)
+
+// decodeManifest decodes multi-document YAML or JSON content into unstructured objects.
+// Empty documents are skipped and objects of kind List are expanded into their items.
+func decodeManifest(content string) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(strings.NewReader(content), 4096)
+
+	var objects []*unstructured.Unstructured
+	for i := 1; ; i++ {
+		var doc map[string]interface{}
+		if err := decoder.Decode(&doc); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to decode document %d: %w", i, err)
+		}
+		if len(doc) == 0 {
+			continue
+		}
+
+		obj := &unstructured.Unstructured{Object: doc}
+		if obj.IsList() {
+			list, err := obj.ToList()
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode list in document %d: %w", i, err)
+			}
+			for j := range list.Items {
+				objects = append(objects, &list.Items[j])
+			}
+			continue
+		}
+		objects = append(objects, obj)
+	}
+
+	for i, obj := range objects {
+		if obj.GetAPIVersion() == "" || obj.GetKind() == "" {
+			return nil, fmt.Errorf("object %d is missing apiVersion or kind", i+1)
+		}
+		if obj.GetName() == "" {
+			return nil, fmt.Errorf("object %d (%s) is missing metadata.name", i+1, obj.GetKind())
+		}
+	}
+	if len(objects) == 0 {
+		return nil, errors.New("manifest does not contain any objects")
+	}
+
+	return objects, nil
+}
+
+// restMapping resolves the REST mapping for a GroupVersionKind, refreshing the
+// discovery cache once when the kind is unknown (e.g. a freshly installed CRD).
+func (h *Handler) restMapping(gvk schema.GroupVersionKind) (*meta.RESTMapping, error) {
+	mapping, err := h.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if meta.IsNoMatchError(err) {
+		h.mapper.Reset()
+		mapping, err = h.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve resource for %s: %w", gvk.String(), err)
+	}
+	return mapping, nil
+}
+
+// resolveResource resolves a user supplied resource name such as "deploy",
+// "deployments" or "deployments.apps" to its REST mapping.
+func (h *Handler) resolveResource(resource string) (*meta.RESTMapping, error) {
+	mapper := restmapper.NewShortcutExpander(h.mapper, h.discovery, nil)
+
+	var gvr schema.GroupVersionResource
+	var err error
+	fullySpecified, groupResource := schema.ParseResourceArg(strings.ToLower(resource))
+	if fullySpecified != nil {
+		gvr, err = mapper.ResourceFor(*fullySpecified)
+	}
+	if fullySpecified == nil || err != nil {
+		gvr, err = mapper.ResourceFor(groupResource.WithVersion(""))
+	}
+	if meta.IsNoMatchError(err) {
+		h.mapper.Reset()
+		gvr, err = mapper.ResourceFor(groupResource.WithVersion(""))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve resource type %q: %w", resource, err)
+	}
+
+	gvk, err := mapper.KindFor(gvr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve kind for %q: %w", resource, err)
+	}
+	return h.restMapping(gvk)
+}
+
+// resourceInterfaceFor returns a dynamic client for the given object. Namespaced
+// objects without a namespace are defaulted to defaultNamespace (or the
+// handler's default namespace),
+// and the namespace is written back into the object.
+func (h *Handler) resourceInterfaceFor(obj *unstructured.Unstructured, defaultNamespace string) (dynamic.ResourceInterface, *meta.RESTMapping, error) {
+	mapping, err := h.restMapping(obj.GroupVersionKind())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		obj.SetNamespace("")
+		return h.dynamic.Resource(mapping.Resource), mapping, nil
+	}
+
+	if obj.GetNamespace() == "" {
+		ns := defaultNamespace
+		if ns == "" {
+			ns = h.defaultNamespace
+		}
+		obj.SetNamespace(ns)
+	}
+	return h.dynamic.Resource(mapping.Resource).Namespace(obj.GetNamespace()), mapping, nil
+}
+
+// namespacedResource returns a dynamic client for the mapping, scoped to the
+// namespace when the resource is namespaced. An empty namespace defaults to the
+// handler's default namespace.
+func (h *Handler) namespacedResource(mapping *meta.RESTMapping, namespace string) (dynamic.ResourceInterface, string) {
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return h.dynamic.Resource(mapping.Resource), ""
+	}
+	if namespace == "" {
+		namespace = h.defaultNamespace
+	}
+	return h.dynamic.Resource(mapping.Resource).Namespace(namespace), namespace
+}
+
+// objectRef formats an object reference in kubectl style, e.g. deployment.apps/nginx.
+func objectRef(mapping *meta.RESTMapping, obj *unstructured.Unstructured) string {
+	ref := strings.ToLower(mapping.GroupVersionKind.Kind)
+	if mapping.GroupVersionKind.Group != "" {
+		ref += "." + mapping.GroupVersionKind.Group
+	}
+	ref += "/" + obj.GetName()
+	if obj.GetNamespace() != "" {
+		ref += fmt.Sprintf(" (namespace: %s)", obj.GetNamespace())
+	}
+	return ref
+}
@@ -0,0 +1,94 @@
+package tool
+
+import "testing"
+
+func TestDecodeManifest(t *testing.T) {
+	tests := []struct {
+		name      string
+		content   string
+		wantNames []string
+		wantErr   bool
+	}{
+		{
+			name: "single yaml document",
+			content: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: one`,
+			wantNames: []string{"one"},
+		},
+		{
+			name: "multiple documents with empty ones",
+			content: `---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: one
+---
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: two
+`,
+			wantNames: []string{"one", "two"},
+		},
+		{
+			name:      "json object",
+			content:   `{"apiVersion": "v1", "kind": "Service", "metadata": {"name": "svc"}}`,
+			wantNames: []string{"svc"},
+		},
+		{
+			name: "list is expanded",
+			content: `apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: a
+- apiVersion: v1
+  kind: Secret
+  metadata:
+    name: b`,
+			wantNames: []string{"a", "b"},
+		},
+		{
+			name: "missing name",
+			content: `apiVersion: v1
+kind: ConfigMap`,
+			wantErr: true,
+		},
+		{
+			name: "missing kind",
+			content: `apiVersion: v1
+metadata:
+  name: one`,
+			wantErr: true,
+		},
+		{
+			name:    "empty manifest",
+			content: "---\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeManifest(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decodeManifest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(got) != len(tt.wantNames) {
+				t.Fatalf("decodeManifest() got %d objects, want %d", len(got), len(tt.wantNames))
+			}
+			for i, obj := range got {
+				if obj.GetName() != tt.wantNames[i] {
+					t.Errorf("decodeManifest() object %d name = %v, want %v", i, obj.GetName(), tt.wantNames[i])
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,467 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/discovery"
+)
+
+// maxOwnerTreeNodes bounds how many objects are fetched for status summaries.
+const maxOwnerTreeNodes = 200
+
+// ownerNode is an object in an ownership tree.
+type ownerNode struct {
+	kind      string
+	name      string
+	namespace string
+	uid       types.UID
+	mapping   *meta.RESTMapping
+	created   time.Time
+	children  []*ownerNode
+}
+
+// ownerTreeResult is the structured output of owner_tree. Nodes lists the
+// tree depth first, starting with the root; each node names its owner.
+type ownerTreeResult struct {
+	Nodes []ownerTreeEntry `json:"nodes"`
+	Notes []string         `json:"notes,omitempty"`
+}
+
+type ownerTreeEntry struct {
+	Kind      string    `json:"kind"`
+	Name      string    `json:"name"`
+	Namespace string    `json:"namespace,omitempty"`
+	UID       types.UID `json:"uid"`
+	// Owner is the UID of the node's owner in the tree, empty for the root.
+	Owner     types.UID `json:"owner,omitempty"`
+	Depth     int       `json:"depth"`
+	Status    string    `json:"status,omitempty"`
+	Created   string    `json:"created,omitempty"`
+	Requested bool      `json:"requested,omitempty"`
+}
+
+// ownerTreeEntries flattens the tree depth first.
+func ownerTreeEntries(node *ownerNode, owner types.UID, depth int, target types.UID, statuses map[types.UID]string) []ownerTreeEntry {
+	created := ""
+	if !node.created.IsZero() {
+		created = node.created.UTC().Format(time.RFC3339)
+	}
+	entries := []ownerTreeEntry{{
+		Kind:      node.kind,
+		Name:      node.name,
+		Namespace: node.namespace,
+		UID:       node.uid,
+		Owner:     owner,
+		Depth:     depth,
+		Status:    statuses[node.uid],
+		Created:   created,
+		Requested: node.uid == target,
+	}}
+	for _, child := range node.children {
+		entries = append(entries, ownerTreeEntries(child, node.uid, depth+1, target, statuses)...)
+	}
+	return entries
+}
+
+func (h *Handler) registerOwner(m *server.MCPServer) {
+	h.addTool(m, mcp.NewTool("owner_tree",
+		mcp.WithDescription("Show the ownership tree of an object: walks ownerReferences up to the root controller and down to all dependents, rendering kind, name, status summary and age. Works for any kind including custom resources"),
+		mcp.WithString("resource",
+			mcp.Description("Resource type of the object (e.g., 'pod', 'deployments.apps', 'certificates.cert-manager.io')"),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of the object"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the object (defaults to the current context's namespace)"),
+		),
+		mcp.WithOutputSchema[ownerTreeResult](),
+	), mcp.NewTypedToolHandler[OwnerTreeArgs](h.ownerTreeHandler()))
+}
+
+type OwnerTreeArgs struct {
+	Resource  string `json:"resource"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+func (h *Handler) ownerTreeHandler() mcp.TypedToolHandlerFunc[OwnerTreeArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args OwnerTreeArgs,
+	) (*mcp.CallToolResult, error) {
+		mapping, err := h.resolveResource(args.Resource)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to resolve resource", err), nil
+		}
+		ri, namespace := h.namespacedResource(mapping, args.Namespace)
+		obj, err := ri.Get(ctx, args.Name, metav1.GetOptions{})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr(fmt.Sprintf("failed to get %s %s", mapping.GroupVersionKind.Kind, args.Name), err), nil
+		}
+
+		root, notes := h.ownerRoot(ctx, obj)
+
+		// A namespaced root only has dependents in its own namespace.
+		listNamespace, clusterScoped := root.GetNamespace(), false
+		if listNamespace == "" {
+			listNamespace, clusterScoped = namespace, true
+		}
+		dependents, err := h.dependentsIndex(ctx, listNamespace, clusterScoped)
+		if err != nil {
+			notes = append(notes, err.Error())
+		}
+
+		rootMapping, err := h.restMapping(root.GroupVersionKind())
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to resolve root owner", err), nil
+		}
+		tree := &ownerNode{
+			kind:      root.GetKind(),
+			name:      root.GetName(),
+			namespace: root.GetNamespace(),
+			uid:       root.GetUID(),
+			mapping:   rootMapping,
+			created:   root.GetCreationTimestamp().Time,
+		}
+		buildOwnerTree(tree, dependents, map[types.UID]bool{})
+
+		statuses := map[types.UID]string{root.GetUID(): statusSummary(root), obj.GetUID(): statusSummary(obj)}
+		h.fetchStatuses(ctx, tree, statuses)
+
+		var sb strings.Builder
+		renderOwnerTree(&sb, tree, obj.GetUID(), statuses, time.Now(), "", "")
+		for _, note := range notes {
+			sb.WriteString("\nNote: " + note)
+		}
+		structured := ownerTreeResult{Nodes: ownerTreeEntries(tree, "", 0, obj.GetUID(), statuses), Notes: notes}
+		return mcp.NewToolResultStructured(structured, strings.TrimRight(sb.String(), "\n")), nil
+	}
+}
+
+// ownerRoot follows the controller (or first) ownerReference of the object up
+// to an object without owners. Owners that can't be resolved end the walk with
+// a note.
+func (h *Handler) ownerRoot(ctx context.Context, obj *unstructured.Unstructured) (*unstructured.Unstructured, []string) {
+	var notes []string
+	visited := map[types.UID]bool{obj.GetUID(): true}
+	current := obj
+	for {
+		refs := current.GetOwnerReferences()
+		if len(refs) == 0 {
+			return current, notes
+		}
+		ref := refs[0]
+		for _, r := range refs {
+			if r.Controller != nil && *r.Controller {
+				ref = r
+				break
+			}
+		}
+		if visited[ref.UID] {
+			return current, append(notes, fmt.Sprintf("ownership cycle detected at %s/%s", ref.Kind, ref.Name))
+		}
+		visited[ref.UID] = true
+
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			return current, append(notes, fmt.Sprintf("invalid owner apiVersion %q", ref.APIVersion))
+		}
+		ownerMapping, err := h.restMapping(gv.WithKind(ref.Kind))
+		if err != nil {
+			return current, append(notes, fmt.Sprintf("owner %s/%s: %v", ref.Kind, ref.Name, err))
+		}
+		ri, _ := h.namespacedResource(ownerMapping, current.GetNamespace())
+		owner, err := ri.Get(ctx, ref.Name, metav1.GetOptions{})
+		if err != nil {
+			return current, append(notes, fmt.Sprintf("owner %s/%s could not be fetched: %v", ref.Kind, ref.Name, err))
+		}
+		if owner.GetUID() != ref.UID {
+			return current, append(notes, fmt.Sprintf("owner %s/%s has a different UID than referenced; the original owner was deleted", ref.Kind, ref.Name))
+		}
+		current = owner
+	}
+}
+
+// ownerIndexTTL is how long the dependents index of a namespace is reused, so
+// walking several trees in a row doesn't list every resource type each time.
+const ownerIndexTTL = 15 * time.Second
+
+// ownerIndexKey identifies a dependents index: the namespace and whether
+// cluster-scoped resources are included.
+type ownerIndexKey struct {
+	namespace     string
+	clusterScoped bool
+}
+
+type ownerIndexEntry struct {
+	index   map[types.UID][]*ownerNode
+	err     error
+	expires time.Time
+}
+
+// ownerIndexCache keeps recently built dependents indexes. Indexes are not
+// modified once built, so callers share them.
+type ownerIndexCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	now     func() time.Time
+	entries map[ownerIndexKey]ownerIndexEntry
+}
+
+func newOwnerIndexCache(ttl time.Duration) *ownerIndexCache {
+	return &ownerIndexCache{ttl: ttl, now: time.Now, entries: map[ownerIndexKey]ownerIndexEntry{}}
+}
+
+func (c *ownerIndexCache) get(key ownerIndexKey) (ownerIndexEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || c.now().After(entry.expires) {
+		return ownerIndexEntry{}, false
+	}
+	return entry, true
+}
+
+func (c *ownerIndexCache) set(key ownerIndexKey, index map[types.UID][]*ownerNode, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.now()
+	for k, entry := range c.entries {
+		if now.After(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	c.entries[key] = ownerIndexEntry{index: index, err: err, expires: now.Add(c.ttl)}
+}
+
+// ownerIndexResources returns the mappings of the resource types that can be
+// dependents: listable, not a subresource and not an Event. Owner references
+// never cross namespaces, so only namespaced types are returned unless
+// clusterScoped is set for a cluster-scoped root.
+func ownerIndexResources(lists []*metav1.APIResourceList, clusterScoped bool) []*meta.RESTMapping {
+	var mappings []*meta.RESTMapping
+	for _, list := range discovery.FilteredBy(discovery.SupportsAllVerbs{Verbs: []string{"list"}}, lists) {
+		gv, err := schema.ParseGroupVersion(list.GroupVersion)
+		if err != nil {
+			continue
+		}
+		for _, r := range list.APIResources {
+			if strings.Contains(r.Name, "/") || (gv.Group == "" || gv.Group == "events.k8s.io") && r.Kind == "Event" {
+				continue
+			}
+			if !r.Namespaced && !clusterScoped {
+				continue
+			}
+			mapping := &meta.RESTMapping{
+				Resource:         gv.WithResource(r.Name),
+				GroupVersionKind: gv.WithKind(r.Kind),
+				Scope:            meta.RESTScopeRoot,
+			}
+			if r.Namespaced {
+				mapping.Scope = meta.RESTScopeNamespace
+			}
+			mappings = append(mappings, mapping)
+		}
+	}
+	return mappings
+}
+
+// dependentsIndex lists the metadata of the resource types that can be
+// dependents in the namespace (and cluster-scoped ones if clusterScoped is
+// set) and indexes them by owner UID. Indexes are cached for ownerIndexTTL.
+func (h *Handler) dependentsIndex(ctx context.Context, namespace string, clusterScoped bool) (map[types.UID][]*ownerNode, error) {
+	key := ownerIndexKey{namespace: namespace, clusterScoped: clusterScoped}
+	if entry, ok := h.ownerIndexes.get(key); ok {
+		return entry.index, entry.err
+	}
+
+	lists, err := h.discovery.ServerPreferredResources()
+	if err != nil && len(lists) == 0 {
+		return nil, fmt.Errorf("failed to discover resources: %w", err)
+	}
+
+	index := map[types.UID][]*ownerNode{}
+	var failed []string
+	for _, mapping := range ownerIndexResources(lists, clusterScoped) {
+		ri := h.metadata.Resource(mapping.Resource)
+		var items *metav1.PartialObjectMetadataList
+		if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+			items, err = ri.Namespace(namespace).List(ctx, metav1.ListOptions{})
+		} else {
+			items, err = ri.List(ctx, metav1.ListOptions{})
+		}
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			log.Debug().Err(err).Str("resource", mapping.Resource.String()).Msg("Skipping resource in owner tree")
+			failed = append(failed, mapping.Resource.GroupResource().String())
+			continue
+		}
+
+		for _, item := range items.Items {
+			for _, ref := range item.OwnerReferences {
+				index[ref.UID] = append(index[ref.UID], &ownerNode{
+					kind:      mapping.GroupVersionKind.Kind,
+					name:      item.Name,
+					namespace: item.Namespace,
+					uid:       item.UID,
+					mapping:   mapping,
+					created:   item.CreationTimestamp.Time,
+				})
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		err = fmt.Errorf("dependents may be incomplete, could not list %s", strings.Join(failed, ", "))
+	} else {
+		err = nil
+	}
+	h.ownerIndexes.set(key, index, err)
+	return index, err
+}
+
+// buildOwnerTree attaches the dependents of every node recursively, sorted by
+// kind and name.
+func buildOwnerTree(node *ownerNode, dependents map[types.UID][]*ownerNode, visited map[types.UID]bool) {
+	visited[node.uid] = true
+	for _, child := range dependents[node.uid] {
+		if visited[child.uid] {
+			continue
+		}
+		c := *child
+		node.children = append(node.children, &c)
+	}
+	sort.Slice(node.children, func(i, j int) bool {
+		a, b := node.children[i], node.children[j]
+		if a.kind != b.kind {
+			return a.kind < b.kind
+		}
+		return a.name < b.name
+	})
+	for _, child := range node.children {
+		buildOwnerTree(child, dependents, visited)
+	}
+}
+
+// fetchStatuses gets the full objects of the tree to summarize their status,
+// up to maxOwnerTreeNodes objects.
+func (h *Handler) fetchStatuses(ctx context.Context, node *ownerNode, statuses map[types.UID]string) {
+	if len(statuses) >= maxOwnerTreeNodes {
+		return
+	}
+	if _, ok := statuses[node.uid]; !ok {
+		ri, _ := h.namespacedResource(node.mapping, node.namespace)
+		if obj, err := ri.Get(ctx, node.name, metav1.GetOptions{}); err == nil {
+			statuses[node.uid] = statusSummary(obj)
+		}
+	}
+	for _, child := range node.children {
+		h.fetchStatuses(ctx, child, statuses)
+	}
+}
+
+func renderOwnerTree(sb *strings.Builder, node *ownerNode, target types.UID, statuses map[types.UID]string, now time.Time, prefix, childPrefix string) {
+	sb.WriteString(prefix + node.kind + "/" + node.name)
+
+	var details []string
+	if node.namespace != "" && prefix == "" {
+		details = append(details, "namespace: "+node.namespace)
+	}
+	if status := statuses[node.uid]; status != "" {
+		details = append(details, status)
+	}
+	if !node.created.IsZero() {
+		details = append(details, "age: "+duration.HumanDuration(now.Sub(node.created)))
+	}
+	if len(details) > 0 {
+		sb.WriteString(" (" + strings.Join(details, ", ") + ")")
+	}
+	if node.uid == target {
+		sb.WriteString(" <-- requested")
+	}
+	sb.WriteString("\n")
+
+	for i, child := range node.children {
+		if i == len(node.children)-1 {
+			renderOwnerTree(sb, child, target, statuses, now, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			renderOwnerTree(sb, child, target, statuses, now, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+// statusSummary describes the status of any object in a few words, based on
+// the common conventions: phase, replica counts and Ready/Available conditions.
+func statusSummary(obj *unstructured.Unstructured) string {
+	var parts []string
+
+	if phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase"); phase != "" {
+		parts = append(parts, phase)
+	}
+
+	if obj.GetKind() == "Pod" {
+		statuses, _, _ := unstructured.NestedSlice(obj.Object, "status", "containerStatuses")
+		ready, restarts := 0, int64(0)
+		for _, s := range statuses {
+			status, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			if r, _, _ := unstructured.NestedBool(status, "ready"); r {
+				ready++
+			}
+			count, _, _ := unstructured.NestedInt64(status, "restartCount")
+			restarts += count
+		}
+		parts = append(parts, fmt.Sprintf("%d/%d ready", ready, len(statuses)))
+		if restarts > 0 {
+			parts = append(parts, fmt.Sprintf("%d restarts", restarts))
+		}
+		return strings.Join(parts, ", ")
+	}
+
+	if desired, found, _ := unstructured.NestedInt64(obj.Object, "spec", "replicas"); found {
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "readyReplicas")
+		parts = append(parts, fmt.Sprintf("%d/%d ready", ready, desired))
+	} else if desired, found, _ := unstructured.NestedInt64(obj.Object, "status", "desiredNumberScheduled"); found {
+		ready, _, _ := unstructured.NestedInt64(obj.Object, "status", "numberReady")
+		parts = append(parts, fmt.Sprintf("%d/%d ready", ready, desired))
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		conditionType, _, _ := unstructured.NestedString(condition, "type")
+		switch conditionType {
+		case "Ready", "Available", "Complete", "Failed", "Synced", "Healthy":
+			status, _, _ := unstructured.NestedString(condition, "status")
+			parts = append(parts, conditionType+"="+status)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
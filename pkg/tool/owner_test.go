@@ -0,0 +1,216 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/discovery/cached/memory"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	metadatafake "k8s.io/client-go/metadata/fake"
+	clienttesting "k8s.io/client-go/testing"
+)
+
+func TestStatusSummary(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  map[string]interface{}
+		want string
+	}{
+		{
+			name: "pod",
+			obj: map[string]interface{}{"kind": "Pod", "status": map[string]interface{}{
+				"phase": "Running",
+				"containerStatuses": []interface{}{
+					map[string]interface{}{"ready": true, "restartCount": int64(0)},
+					map[string]interface{}{"ready": false, "restartCount": int64(3)},
+				},
+			}},
+			want: "Running, 1/2 ready, 3 restarts",
+		},
+		{
+			name: "deployment",
+			obj: map[string]interface{}{"kind": "Deployment",
+				"spec": map[string]interface{}{"replicas": int64(3)},
+				"status": map[string]interface{}{
+					"readyReplicas": int64(2),
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Available", "status": "False"},
+						map[string]interface{}{"type": "Progressing", "status": "True"},
+					},
+				}},
+			want: "2/3 ready, Available=False",
+		},
+		{
+			name: "daemonset",
+			obj: map[string]interface{}{"kind": "DaemonSet", "status": map[string]interface{}{
+				"desiredNumberScheduled": int64(4), "numberReady": int64(4),
+			}},
+			want: "4/4 ready",
+		},
+		{
+			name: "custom resource",
+			obj: map[string]interface{}{"kind": "Certificate", "status": map[string]interface{}{
+				"conditions": []interface{}{map[string]interface{}{"type": "Ready", "status": "True"}},
+			}},
+			want: "Ready=True",
+		},
+		{
+			name: "configmap",
+			obj:  map[string]interface{}{"kind": "ConfigMap"},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statusSummary(&unstructured.Unstructured{Object: tt.obj}); got != tt.want {
+				t.Errorf("statusSummary() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderOwnerTree(t *testing.T) {
+	now := time.Now()
+	root := &ownerNode{kind: "Deployment", name: "web", namespace: "prod", uid: "d", created: now.Add(-48 * time.Hour)}
+	dependents := map[types.UID][]*ownerNode{
+		"d": {
+			{kind: "ReplicaSet", name: "web-new", uid: "rs2"},
+			{kind: "ReplicaSet", name: "web-old", uid: "rs1"},
+		},
+		"rs2": {
+			{kind: "Pod", name: "web-new-b", uid: "p2"},
+			{kind: "Pod", name: "web-new-a", uid: "p1"},
+		},
+	}
+	buildOwnerTree(root, dependents, map[types.UID]bool{})
+
+	var sb strings.Builder
+	renderOwnerTree(&sb, root, "p1", map[types.UID]string{"d": "2/2 ready", "p1": "Running"}, now, "", "")
+	want := `Deployment/web (namespace: prod, 2/2 ready, age: 2d)
+├── ReplicaSet/web-new
+│   ├── Pod/web-new-a (Running) <-- requested
+│   └── Pod/web-new-b
+└── ReplicaSet/web-old
+`
+	if got := sb.String(); got != want {
+		t.Errorf("renderOwnerTree() =\n%s\nwant\n%s", got, want)
+	}
+
+	entries := ownerTreeEntries(root, "", 0, "p1", map[types.UID]string{"p1": "Running"})
+	var names []string
+	for _, e := range entries {
+		names = append(names, fmt.Sprintf("%d:%s/%s<%s", e.Depth, e.Kind, e.Name, e.Owner))
+	}
+	wantNames := []string{"0:Deployment/web<", "1:ReplicaSet/web-new<d", "2:Pod/web-new-a<rs2", "2:Pod/web-new-b<rs2", "1:ReplicaSet/web-old<d"}
+	if !reflect.DeepEqual(names, wantNames) {
+		t.Errorf("ownerTreeEntries() = %v, want %v", names, wantNames)
+	}
+	if !entries[2].Requested || entries[2].Status != "Running" || entries[0].Created == "" {
+		t.Errorf("ownerTreeEntries() requested node = %+v, root = %+v", entries[2], entries[0])
+	}
+}
+
+func TestDependentsIndex(t *testing.T) {
+	discoveryClient := &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{
+		{
+			GroupVersion: "v1",
+			APIResources: []metav1.APIResource{
+				{Name: "pods", Kind: "Pod", Namespaced: true, Verbs: []string{"get", "list"}},
+				{Name: "pods/log", Kind: "Pod", Namespaced: true, Verbs: []string{"get"}},
+				{Name: "events", Kind: "Event", Namespaced: true, Verbs: []string{"list"}},
+				{Name: "nodes", Kind: "Node", Verbs: []string{"list"}},
+				{Name: "bindings", Kind: "Binding", Namespaced: true, Verbs: []string{"create"}},
+			},
+		},
+		{
+			GroupVersion: "apps/v1",
+			APIResources: []metav1.APIResource{
+				{Name: "replicasets", Kind: "ReplicaSet", Namespaced: true, Verbs: []string{"list"}},
+			},
+		},
+	}}}
+
+	scheme := runtime.NewScheme()
+	metav1.AddMetaToScheme(scheme)
+	owned := func(gvk schema.GroupVersionKind, namespace, name string, owner types.UID) *metav1.PartialObjectMetadata {
+		obj := &metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			UID:             types.UID(namespace + "/" + name),
+			OwnerReferences: []metav1.OwnerReference{{UID: owner}},
+		}}
+		obj.SetGroupVersionKind(gvk)
+		return obj
+	}
+	metadataClient := metadatafake.NewSimpleMetadataClient(scheme,
+		owned(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "ReplicaSet"}, "shop", "web-1", "deploy-web"),
+		owned(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, "shop", "web-1-a", "shop/web-1"),
+		owned(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, "other", "web-1-b", "shop/web-1"),
+	)
+
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	h := &Handler{
+		discovery:    memory.NewMemCacheClient(discoveryClient),
+		metadata:     metadataClient,
+		ownerIndexes: newOwnerIndexCache(ownerIndexTTL),
+	}
+	h.ownerIndexes.now = func() time.Time { return now }
+
+	listed := func() []string {
+		var resources []string
+		for _, action := range metadataClient.Actions() {
+			if action.GetVerb() == "list" {
+				resources = append(resources, action.GetResource().Resource+"@"+action.GetNamespace())
+			}
+		}
+		metadataClient.ClearActions()
+		sort.Strings(resources)
+		return resources
+	}
+
+	index, err := h.dependentsIndex(context.Background(), "shop", false)
+	if err != nil {
+		t.Fatalf("dependentsIndex() error = %v", err)
+	}
+	if got, want := listed(), []string{"pods@shop", "replicasets@shop"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("listed %v, want %v", got, want)
+	}
+	if got := index["deploy-web"]; len(got) != 1 || got[0].kind != "ReplicaSet" || got[0].name != "web-1" {
+		t.Errorf("dependents of the deployment = %v, want ReplicaSet web-1", got)
+	}
+	if got := index["shop/web-1"]; len(got) != 1 || got[0].name != "web-1-a" {
+		t.Errorf("dependents of the replicaset = %v, want only pod web-1-a in the namespace", got)
+	}
+
+	if _, err := h.dependentsIndex(context.Background(), "shop", false); err != nil {
+		t.Fatalf("dependentsIndex() error = %v", err)
+	}
+	if got := listed(); len(got) != 0 {
+		t.Errorf("cached index listed %v again", got)
+	}
+
+	if _, err := h.dependentsIndex(context.Background(), "default", true); err != nil {
+		t.Fatalf("dependentsIndex() error = %v", err)
+	}
+	if got, want := listed(), []string{"nodes@", "pods@default", "replicasets@default"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("listed %v for a cluster-scoped root, want %v", got, want)
+	}
+
+	now = now.Add(ownerIndexTTL + time.Second)
+	if _, err := h.dependentsIndex(context.Background(), "shop", false); err != nil {
+		t.Fatalf("dependentsIndex() error = %v", err)
+	}
+	if got := listed(); len(got) != 2 {
+		t.Errorf("expired index listed %v, want pods and replicasets again", got)
+	}
+}
@@ -0,0 +1,203 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// defaultPatchType is used when a call doesn't set patch_type.
+const defaultPatchType = "strategic"
+
+var patchTypes = map[string]types.PatchType{
+	"strategic": types.StrategicMergePatchType,
+	"merge":     types.MergePatchType,
+	"json":      types.JSONPatchType,
+}
+
+func (h *Handler) registerPatch(m *server.MCPServer) {
+	h.addTool(m, mcp.NewTool("patch_resource",
+		mcp.WithDescription("Patch a single Kubernetes resource with a strategic merge, JSON merge or JSON patch and show the resulting changes"),
+		mcp.WithString("resource",
+			mcp.Description("Resource type to patch (e.g., deployment, deploy, statefulsets.apps, or a custom resource)"),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of the resource to patch"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the resource (defaults to the server's default namespace, ignored for cluster-scoped resources)"),
+		),
+		mcp.WithString("patch_type",
+			mcp.Description("Patch type: strategic (built-in types only), merge (RFC 7386) or json (RFC 6902)"),
+			mcp.Enum("strategic", "merge", "json"),
+			mcp.DefaultString(defaultPatchType),
+		),
+		mcp.WithString("patch",
+			mcp.Description(`Patch body as JSON or YAML (e.g., {"spec":{"replicas":3}} or [{"op":"replace","path":"/spec/replicas","value":3}])`),
+			mcp.Required(),
+		),
+		mcp.WithBoolean("dry_run",
+			mcp.Description("Run a server-side dry-run without persisting the patch"),
+			mcp.DefaultBool(false),
+		),
+		mcp.WithOutputSchema[patchResourceResult](),
+	), mcp.NewTypedToolHandler[PatchResourceArgs](h.patchResourceHandler()))
+}
+
+type PatchResourceArgs struct {
+	Resource  string `json:"resource"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+	PatchType string `json:"patch_type"`
+	Patch     string `json:"patch"`
+	DryRun    bool   `json:"dry_run"`
+}
+
+// patchResourceResult is the structured output of patch_resource.
+type patchResourceResult struct {
+	Ref       string `json:"ref"`
+	Namespace string `json:"namespace,omitempty"`
+	PatchType string `json:"patchType"`
+	DryRun    bool   `json:"dryRun"`
+	Changed   bool   `json:"changed"`
+	Added     int    `json:"added"`
+	Removed   int    `json:"removed"`
+	Diff      string `json:"diff,omitempty"`
+}
+
+func (h *Handler) patchResourceHandler() mcp.TypedToolHandlerFunc[PatchResourceArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args PatchResourceArgs,
+	) (*mcp.CallToolResult, error) {
+		if args.PatchType == "" {
+			args.PatchType = defaultPatchType
+		}
+		patchType, body, opts, err := patchRequest(args, h.fieldManager)
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		mapping, err := h.resolveResource(args.Resource)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to resolve resource", err), nil
+		}
+		ri, namespace := h.namespacedResource(mapping, args.Namespace)
+
+		before, err := ri.Get(ctx, args.Name, metav1.GetOptions{})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to get resource", err), nil
+		}
+
+		after, err := ri.Patch(ctx, args.Name, patchType, body, opts)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("patch failed", err), nil
+		}
+
+		ref := objectRef(mapping, after)
+		diff, added, removed, err := patchDiff(ref, before, after)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to diff patched object", err), nil
+		}
+
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("Patched %s", ref))
+		if namespace != "" {
+			sb.WriteString(" in namespace " + namespace)
+		}
+		sb.WriteString(fmt.Sprintf(" with %s patch", args.PatchType))
+		if args.DryRun {
+			sb.WriteString(" (dry run)")
+		}
+		if diff == "" {
+			sb.WriteString(": no changes\n")
+		} else {
+			sb.WriteString(fmt.Sprintf(": +%d -%d\n\n%s", added, removed, diff))
+		}
+
+		return mcp.NewToolResultStructured(patchResourceResult{
+			Ref:       ref,
+			Namespace: namespace,
+			PatchType: args.PatchType,
+			DryRun:    args.DryRun,
+			Changed:   diff != "",
+			Added:     added,
+			Removed:   removed,
+			Diff:      diff,
+		}, sb.String()), nil
+	}
+}
+
+// patchDiff diffs the object before and after the patch, with status, managed
+// fields and Secret data left out.
+func patchDiff(ref string, before, after *unstructured.Unstructured) (string, int, int, error) {
+	beforeObj, afterObj := normalizeObject(before), normalizeObject(after)
+	redactSecretData(beforeObj, afterObj)
+
+	beforeYAML, err := yaml.Marshal(beforeObj)
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("failed to marshal original object: %w", err)
+	}
+	afterYAML, err := yaml.Marshal(afterObj)
+	if err != nil {
+		return "", 0, 0, fmt.Errorf("failed to marshal patched object: %w", err)
+	}
+	return unifiedDiff(string(beforeYAML), string(afterYAML), "before/"+ref, "after/"+ref)
+}
+
+// patchRequest validates the patch arguments and returns the patch type, the
+// patch body as JSON and the patch options. Merge patches must be a JSON
+// object and JSON patches a list of operations.
+func patchRequest(args PatchResourceArgs, fieldManager string) (types.PatchType, []byte, metav1.PatchOptions, error) {
+	opts := metav1.PatchOptions{FieldManager: fieldManager}
+	if args.PatchType == "" {
+		args.PatchType = defaultPatchType
+	}
+	patchType, ok := patchTypes[args.PatchType]
+	if !ok {
+		return "", nil, opts, fmt.Errorf("unsupported patch_type '%s' (expected strategic, merge or json)", args.PatchType)
+	}
+	if strings.TrimSpace(args.Patch) == "" {
+		return "", nil, opts, fmt.Errorf("patch must not be empty")
+	}
+	body, err := yaml.YAMLToJSON([]byte(args.Patch))
+	if err != nil {
+		return "", nil, opts, fmt.Errorf("patch is not valid JSON or YAML: %w", err)
+	}
+
+	if patchType == types.JSONPatchType {
+		var ops []map[string]interface{}
+		if err := json.Unmarshal(body, &ops); err != nil {
+			return "", nil, opts, fmt.Errorf("json patch must be a list of operations: %w", err)
+		}
+		for i, op := range ops {
+			if _, ok := op["op"].(string); !ok {
+				return "", nil, opts, fmt.Errorf("json patch operation %d has no op", i)
+			}
+			if _, ok := op["path"].(string); !ok {
+				return "", nil, opts, fmt.Errorf("json patch operation %d has no path", i)
+			}
+		}
+	} else {
+		var obj map[string]interface{}
+		if err := json.Unmarshal(body, &obj); err != nil || obj == nil {
+			return "", nil, opts, fmt.Errorf("%s patch must be an object", args.PatchType)
+		}
+	}
+
+	if args.DryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	return patchType, body, opts, nil
+}
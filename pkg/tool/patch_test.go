@@ -0,0 +1,154 @@
+package tool
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestPatchRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		args       PatchResourceArgs
+		wantType   types.PatchType
+		wantBody   string
+		wantDryRun []string
+		wantErr    string
+	}{
+		{
+			name:     "strategic by default",
+			args:     PatchResourceArgs{Patch: `{"spec":{"replicas":3}}`},
+			wantType: types.StrategicMergePatchType,
+			wantBody: `{"spec":{"replicas":3}}`,
+		},
+		{
+			name:     "merge patch from yaml",
+			args:     PatchResourceArgs{PatchType: "merge", Patch: "metadata:\n  labels:\n    tier: web\n"},
+			wantType: types.MergePatchType,
+			wantBody: `{"metadata":{"labels":{"tier":"web"}}}`,
+		},
+		{
+			name:     "json patch",
+			args:     PatchResourceArgs{PatchType: "json", Patch: `[{"op":"replace","path":"/spec/replicas","value":3}]`},
+			wantType: types.JSONPatchType,
+			wantBody: `[{"op":"replace","path":"/spec/replicas","value":3}]`,
+		},
+		{
+			name:       "dry run",
+			args:       PatchResourceArgs{PatchType: "merge", Patch: `{"spec":{"paused":true}}`, DryRun: true},
+			wantType:   types.MergePatchType,
+			wantBody:   `{"spec":{"paused":true}}`,
+			wantDryRun: []string{metav1.DryRunAll},
+		},
+		{
+			name:    "unsupported patch type",
+			args:    PatchResourceArgs{PatchType: "apply", Patch: `{}`},
+			wantErr: "unsupported patch_type 'apply'",
+		},
+		{
+			name:    "empty patch",
+			args:    PatchResourceArgs{Patch: "  \n"},
+			wantErr: "patch must not be empty",
+		},
+		{
+			name:    "invalid yaml",
+			args:    PatchResourceArgs{Patch: "spec: [replicas"},
+			wantErr: "patch is not valid JSON or YAML",
+		},
+		{
+			name:    "merge patch that is a list",
+			args:    PatchResourceArgs{PatchType: "merge", Patch: `[{"op":"remove","path":"/spec"}]`},
+			wantErr: "merge patch must be an object",
+		},
+		{
+			name:    "strategic patch that is a scalar",
+			args:    PatchResourceArgs{Patch: `replicas`},
+			wantErr: "strategic patch must be an object",
+		},
+		{
+			name:    "json patch that is an object",
+			args:    PatchResourceArgs{PatchType: "json", Patch: `{"spec":{"replicas":3}}`},
+			wantErr: "json patch must be a list of operations",
+		},
+		{
+			name:    "json patch operation without path",
+			args:    PatchResourceArgs{PatchType: "json", Patch: `[{"op":"remove"}]`},
+			wantErr: "json patch operation 0 has no path",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			patchType, body, opts, err := patchRequest(tt.args, defaultFieldManager)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("patchRequest() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("patchRequest() error = %v", err)
+			}
+			if patchType != tt.wantType || string(body) != tt.wantBody {
+				t.Errorf("patchRequest() = %s %s, want %s %s", patchType, body, tt.wantType, tt.wantBody)
+			}
+			if opts.FieldManager != defaultFieldManager || !reflect.DeepEqual(opts.DryRun, tt.wantDryRun) {
+				t.Errorf("patchRequest() options = %+v, want field manager %q and dry run %v", opts, defaultFieldManager, tt.wantDryRun)
+			}
+		})
+	}
+}
+
+func TestPatchDiff(t *testing.T) {
+	secret := func(labels map[string]interface{}, password string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Secret",
+			"metadata":   map[string]interface{}{"name": "creds", "namespace": "default", "labels": labels},
+			"data":       map[string]interface{}{"password": password, "user": "YWRtaW4="},
+			"stringData": map[string]interface{}{"token": "plain-token"},
+		}}
+	}
+
+	tests := []struct {
+		name      string
+		before    *unstructured.Unstructured
+		after     *unstructured.Unstructured
+		wantLines []string
+	}{
+		{
+			name:      "label patch on a secret",
+			before:    secret(map[string]interface{}{"tier": "web"}, "b2xk"),
+			after:     secret(map[string]interface{}{"tier": "db"}, "b2xk"),
+			wantLines: []string{"-    tier: web", "+    tier: db"},
+		},
+		{
+			name:      "data patch on a secret",
+			before:    secret(map[string]interface{}{"tier": "web"}, "b2xk"),
+			after:     secret(map[string]interface{}{"tier": "web"}, "bmV3"),
+			wantLines: []string{"-  password: REDACTED (before)", "+  password: REDACTED (after)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diff, _, _, err := patchDiff("secret/creds", tt.before, tt.after)
+			if err != nil {
+				t.Fatalf("patchDiff() error = %v", err)
+			}
+			lines := strings.Split(diff, "\n")
+			for _, want := range tt.wantLines {
+				if !containsString(lines, want) {
+					t.Errorf("diff has no line %q:\n%s", want, diff)
+				}
+			}
+			for _, secretValue := range []string{"b2xk", "bmV3", "YWRtaW4=", "plain-token"} {
+				if strings.Contains(diff, secretValue) {
+					t.Errorf("diff contains %q:\n%s", secretValue, diff)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,283 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+// PermissionCheckMode controls what happens to tools the current identity is
+// not allowed to use.
+type PermissionCheckMode string
+
+const (
+	PermissionCheckOff      PermissionCheckMode = "off"
+	PermissionCheckAnnotate PermissionCheckMode = "annotate"
+	PermissionCheckHide     PermissionCheckMode = "hide"
+)
+
+// anyResource matches a permission requirement against rules for any resource.
+const anyResource = ""
+
+// toolPermission is an RBAC requirement of a tool. An empty resource means the
+// verb is needed on some, not further specified, resource.
+type toolPermission struct {
+	verb     string
+	group    string
+	resource string
+}
+
+func (p toolPermission) String() string {
+	if p.resource == anyResource {
+		return p.verb + " on any resource"
+	}
+	if p.group != "" {
+		return fmt.Sprintf("%s %s.%s", p.verb, p.resource, p.group)
+	}
+	return fmt.Sprintf("%s %s", p.verb, p.resource)
+}
+
+// toolPermissions lists the permissions each tool needs. Tools that are not
+// listed (like kubectl_generic or discovery) are always considered usable.
+var toolPermissions = map[string][]toolPermission{
+	"count_pods":     {{verb: "list", resource: "pods"}},
+	"apply_manifest": {{verb: "get"}, {verb: "patch"}},
+	"diff_manifest":  {{verb: "get"}, {verb: "patch"}},
+	"patch_resource": {{verb: "get"}, {verb: "patch"}},
+	"rbac_who_can": {
+		{verb: "list", group: "rbac.authorization.k8s.io", resource: "clusterroles"},
+		{verb: "list", group: "rbac.authorization.k8s.io", resource: "clusterrolebindings"},
+	},
+	"rbac_subject_permissions": {
+		{verb: "list", group: "rbac.authorization.k8s.io", resource: "clusterroles"},
+		{verb: "list", group: "rbac.authorization.k8s.io", resource: "clusterrolebindings"},
+	},
+	"security_scan": {
+		{verb: "list", group: "apps", resource: "deployments"},
+		{verb: "list", resource: "pods"},
+	},
+	"deprecated_apis": {{verb: "list"}},
+	"helm_list":       {{verb: "list", resource: "secrets"}},
+	"helm_status":     {{verb: "list", resource: "secrets"}},
+	"helm_history":    {{verb: "list", resource: "secrets"}},
+	"helm_values":     {{verb: "list", resource: "secrets"}},
+	"helm_manifest":   {{verb: "list", resource: "secrets"}},
+	"create_job_from_cronjob": {
+		{verb: "get", group: "batch", resource: "cronjobs"},
+		{verb: "create", group: "batch", resource: "jobs"},
+	},
+	"suspend_cronjob": {{verb: "patch", group: "batch", resource: "cronjobs"}},
+	"resume_cronjob":  {{verb: "patch", group: "batch", resource: "cronjobs"}},
+	"cronjob_runs": {
+		{verb: "get", group: "batch", resource: "cronjobs"},
+		{verb: "list", group: "batch", resource: "jobs"},
+	},
+	"summarize_resources": {{verb: "list"}},
+	"kubectl_get":         {{verb: "list"}},
+	"kubectl_describe":    {{verb: "get"}},
+	"kubectl_logs":        {{verb: "get", resource: "pods/log"}},
+	"kubectl_create":      {{verb: "create"}},
+	"kubectl_delete":      {{verb: "delete"}},
+	"kubectl_apply":       {{verb: "patch"}},
+	"kubectl_label":       {{verb: "patch"}},
+	"kubectl_annotate":    {{verb: "patch"}},
+}
+
+// loadPermissions fetches the effective rules of the current identity in the
+// default namespace so tool registration can check them.
+func (h *Handler) loadPermissions() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	review, err := h.rulesReview(ctx, h.defaultNamespace)
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to review permissions, all tools will be registered as usable")
+		return
+	}
+	if review.Status.Incomplete {
+		log.Warn().Str("reason", review.Status.EvaluationError).Msg("Permission review is incomplete, only annotating tools")
+	}
+	h.rules = review
+	h.namespaceRules.set(h.defaultNamespace, review)
+}
+
+// namespaceRules caches the effective rules of the current identity per
+// namespace, reviewed the first time a call uses the namespace. The server
+// is bound to the context of its kubeconfig, so one cache covers every call.
+type namespaceRules struct {
+	mu    sync.Mutex
+	rules map[string]*authorizationv1.SelfSubjectRulesReview
+}
+
+func newNamespaceRules() *namespaceRules {
+	return &namespaceRules{rules: map[string]*authorizationv1.SelfSubjectRulesReview{}}
+}
+
+func (r *namespaceRules) get(namespace string) (*authorizationv1.SelfSubjectRulesReview, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	review, ok := r.rules[namespace]
+	return review, ok
+}
+
+func (r *namespaceRules) set(namespace string, review *authorizationv1.SelfSubjectRulesReview) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules[namespace] = review
+}
+
+// rulesFor returns the rules of the current identity in the namespace,
+// reviewing them on first use. It returns nil when the review failed, which
+// is cached too so a failing review isn't repeated on every call, unless the
+// call itself was cancelled.
+func (h *Handler) rulesFor(ctx context.Context, namespace string) *authorizationv1.SelfSubjectRulesReview {
+	if review, ok := h.namespaceRules.get(namespace); ok {
+		return review
+	}
+	reviewCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	review, err := h.rulesReview(reviewCtx, namespace)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil
+		}
+		log.Warn().Err(err).Str("namespace", namespace).Msg("Failed to review permissions")
+		review = nil
+	}
+	h.namespaceRules.set(namespace, review)
+	return review
+}
+
+// missingPermissions returns the requirements of the tool that are not
+// granted by the rules.
+func missingPermissions(rules *authorizationv1.SelfSubjectRulesReview, toolName string) []toolPermission {
+	if rules == nil {
+		return nil
+	}
+
+	var missing []toolPermission
+	for _, p := range toolPermissions[toolName] {
+		if !rulesAllow(rules.Status.ResourceRules, p) {
+			missing = append(missing, p)
+		}
+	}
+	return missing
+}
+
+func permissionList(permissions []toolPermission) string {
+	required := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		required = append(required, p.String())
+	}
+	return strings.Join(required, ", ")
+}
+
+// checkPermissions wraps a tool handler so a failed call in a namespace the
+// current identity lacks the tool's permissions in is annotated with the
+// missing permissions. The rules of each namespace are reviewed on first use.
+func (h *Handler) checkPermissions(toolName string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if h.permissionCheck == PermissionCheckOff || len(toolPermissions[toolName]) == 0 {
+		return handler
+	}
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, req)
+		if result == nil || !result.IsError {
+			return result, err
+		}
+		namespace := PolicyRequest(toolName, req.GetArguments(), h.defaultNamespace).Namespace
+		if namespace == "" {
+			return result, err
+		}
+		if missing := missingPermissions(h.rulesFor(ctx, namespace), toolName); len(missing) > 0 {
+			result.Content = append(result.Content, mcp.NewTextContent(fmt.Sprintf(
+				"The current identity is likely not permitted to do this in namespace %s: missing %s", namespace, permissionList(missing))))
+		}
+		return result, err
+	}
+}
+
+// rulesAllow reports whether any rule grants the permission, following the
+// RBAC wildcard semantics for verbs, groups, resources and subresources.
+func rulesAllow(rules []authorizationv1.ResourceRule, p toolPermission) bool {
+	for _, rule := range rules {
+		if !matchesAny(rule.Verbs, p.verb) {
+			continue
+		}
+		if p.resource == anyResource {
+			if len(rule.Resources) > 0 {
+				return true
+			}
+			continue
+		}
+		if !matchesAny(rule.APIGroups, p.group) {
+			continue
+		}
+		for _, r := range rule.Resources {
+			if resourceMatches(r, p.resource) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func matchesAny(list []string, value string) bool {
+	for _, item := range list {
+		if item == "*" || item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func resourceMatches(rule, resource string) bool {
+	if rule == "*" || rule == resource {
+		return true
+	}
+	// "*/scale" matches the scale subresource of any resource.
+	if strings.HasPrefix(rule, "*/") {
+		_, sub, found := strings.Cut(resource, "/")
+		return found && sub == strings.TrimPrefix(rule, "*/")
+	}
+	return false
+}
+
+// addTool registers a tool the toolset allows, hiding it or annotating its
+// description when the current identity lacks the permissions it needs in the
+// default namespace. Calls are checked against the rate limits and the policy,
+// destructive calls must be confirmed, failed calls are annotated with the
+// permissions missing in their namespace, calls time out or can be cancelled
+// and the output size is limited.
+func (h *Handler) addTool(m *server.MCPServer, tool mcp.Tool, handler server.ToolHandlerFunc) {
+	if !h.toolset.AllowsTool(tool.Name) {
+		log.Debug().Str("tool", tool.Name).Msg("Skipping tool not enabled by the toolset")
+		return
+	}
+	if h.permissionCheck != PermissionCheckOff {
+		if missing := missingPermissions(h.rules, tool.Name); len(missing) > 0 {
+			if h.permissionCheck == PermissionCheckHide && !h.rules.Status.Incomplete {
+				log.Info().Str("tool", tool.Name).Str("missing", permissionList(missing)).Msg("Hiding tool the current identity is not allowed to use")
+				return
+			}
+			tool.Description += fmt.Sprintf(" [Likely not permitted for the current identity in namespace %s: missing %s]",
+				h.defaultNamespace, permissionList(missing))
+		}
+	}
+	if h.policy != nil || (h.destructiveConfirmation && confirmableTools[tool.Name]) {
+		mcp.WithString(confirmTokenArg,
+			mcp.Description("Token returned by a previous call that required confirmation; pass it with otherwise identical arguments to proceed"),
+		)(&tool)
+	}
+	mcp.WithString(timeoutArg,
+		mcp.Description(fmt.Sprintf("Maximum time the call may run, e.g. 30s or 2m (defaults to %s, at most %s)", h.defaultTimeout(tool.Name), h.maxToolTimeout)),
+	)(&tool)
+	m.AddTool(tool, h.limitRate(tool.Name, h.cancellable(h.limitOutput(h.enforcePolicy(tool.Name,
+		h.requireConfirmation(tool.Name, h.checkPermissions(tool.Name, h.limitTime(tool.Name, handler))))))))
+}
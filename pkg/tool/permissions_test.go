@@ -0,0 +1,75 @@
+package tool
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	authorizationv1 "k8s.io/api/authorization/v1"
+)
+
+func TestRulesAllow(t *testing.T) {
+	rules := []authorizationv1.ResourceRule{
+		{Verbs: []string{"get", "list"}, APIGroups: []string{""}, Resources: []string{"pods", "pods/log"}},
+		{Verbs: []string{"patch"}, APIGroups: []string{"apps"}, Resources: []string{"deployments", "*/scale"}},
+		{Verbs: []string{"*"}, APIGroups: []string{"batch"}, Resources: []string{"jobs"}},
+	}
+
+	tests := []struct {
+		name       string
+		permission toolPermission
+		want       bool
+	}{
+		{"exact resource", toolPermission{verb: "list", resource: "pods"}, true},
+		{"subresource", toolPermission{verb: "get", resource: "pods/log"}, true},
+		{"wrong verb", toolPermission{verb: "delete", resource: "pods"}, false},
+		{"wrong group", toolPermission{verb: "patch", group: "extensions", resource: "deployments"}, false},
+		{"wildcard subresource", toolPermission{verb: "patch", group: "apps", resource: "statefulsets/scale"}, true},
+		{"wildcard subresource does not match main resource", toolPermission{verb: "patch", group: "apps", resource: "statefulsets"}, false},
+		{"wildcard verb", toolPermission{verb: "delete", group: "batch", resource: "jobs"}, true},
+		{"any resource", toolPermission{verb: "patch"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rulesAllow(rules, tt.permission); got != tt.want {
+				t.Errorf("rulesAllow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	nonResource := []authorizationv1.ResourceRule{{Verbs: []string{"create"}, APIGroups: []string{"authorization.k8s.io"}}}
+	if rulesAllow(nonResource, toolPermission{verb: "create"}) {
+		t.Errorf("rulesAllow() = true for a rule without resources, want false")
+	}
+}
+
+func TestCheckPermissions(t *testing.T) {
+	h := &Handler{permissionCheck: PermissionCheckAnnotate, defaultNamespace: "default", namespaceRules: newNamespaceRules()}
+	h.namespaceRules.set("default", &authorizationv1.SelfSubjectRulesReview{Status: authorizationv1.SubjectRulesReviewStatus{
+		ResourceRules: []authorizationv1.ResourceRule{{Verbs: []string{"list"}, APIGroups: []string{""}, Resources: []string{"pods"}}},
+	}})
+	h.namespaceRules.set("prod", &authorizationv1.SelfSubjectRulesReview{})
+	handler := h.checkPermissions("count_pods", func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("forbidden"), nil
+	})
+
+	tests := []struct {
+		namespace string
+		wantNote  bool
+	}{
+		{namespace: "default"},
+		{namespace: "prod", wantNote: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.namespace, func(t *testing.T) {
+			req := mcp.CallToolRequest{}
+			req.Params.Arguments = map[string]interface{}{"namespace": tt.namespace}
+			result, _ := handler(context.Background(), req)
+			note := strings.Contains(resultText(result), "missing list pods")
+			if note != tt.wantNote {
+				t.Errorf("result = %q, want missing permissions noted: %v", resultText(result), tt.wantNote)
+			}
+		})
+	}
+}
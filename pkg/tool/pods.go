@@ -10,12 +10,13 @@ import (
 )
 
 func (h *Handler) registerPods(m *server.MCPServer) {
-	m.AddTool(mcp.NewTool("count_pods",
+	h.addTool(m, mcp.NewTool("count_pods",
 		mcp.WithDescription("Count Pods in a Kubernetes namespace"),
 		mcp.WithString("namespace",
 			mcp.Description("Namespace to count (empty for all)"),
 			mcp.DefaultString("default"),
 		),
+		mcp.WithOutputSchema[countPodsResult](),
 	), mcp.NewTypedToolHandler[CountPodsArgs](h.countPodsHandler()))
 }
 
@@ -23,6 +24,13 @@ type CountPodsArgs struct {
 	Namespace string `json:"namespace"`
 }
 
+// countPodsResult is the structured output of count_pods. An empty namespace
+// means all namespaces.
+type countPodsResult struct {
+	Namespace string `json:"namespace"`
+	Count     int    `json:"count"`
+}
+
 func (h *Handler) countPodsHandler() mcp.TypedToolHandlerFunc[CountPodsArgs] {
 	return func(
 		ctx context.Context,
@@ -35,7 +43,8 @@ func (h *Handler) countPodsHandler() mcp.TypedToolHandlerFunc[CountPodsArgs] {
 		if err != nil {
 			return mcp.NewToolResultErrorFromErr("count pods failed", err), nil
 		}
-		return mcp.NewToolResultText(
+		return mcp.NewToolResultStructured(
+			countPodsResult{Namespace: args.Namespace, Count: len(podsCount.Items)},
 			fmt.Sprintf("Found %d pods", len(podsCount.Items)),
 		), nil
 	}
@@ -0,0 +1,135 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/idebeijer/kube-mcp-server/pkg/policy"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WithPolicy evaluates every tool call against the policy before running it.
+func WithPolicy(engine *policy.Engine) Option {
+	return func(h *Handler) {
+		h.policy = engine
+	}
+}
+
+// PolicyRequest describes a tool call for policy evaluation. The verb and
+// resource come from the permissions the tool needs, overridden by a resource
+// argument; kubectl_generic calls are described by their subcommand, resource
+// and flags, which are added to the arguments as "flags" (and "subcommand"
+// for commands like rollout); a resource or namespace their arguments leave
+// ambiguous is reported as unknown. The
+// namespace falls back to defaultNamespace unless the call spans all
+// namespaces.
+func PolicyRequest(toolName string, args map[string]interface{}, defaultNamespace string) policy.Request {
+	req := policy.Request{Tool: toolName, Args: map[string]interface{}{}}
+	for k, v := range args {
+		req.Args[k] = v
+	}
+
+	if permissions := toolPermissions[toolName]; len(permissions) > 0 {
+		primary := permissions[len(permissions)-1]
+		req.Verb = primary.verb
+		req.Resource = primary.resource
+	}
+	if resource, ok := args["resource"].(string); ok && resource != "" {
+		req.Resource = resource
+	}
+	req.Namespace, _ = args["namespace"].(string)
+	allNamespaces, _ := args["all_namespaces"].(bool)
+
+	if toolName == "kubectl_generic" {
+		var kubectlArgs KubectlArgs
+		if data, err := json.Marshal(args["args"]); err == nil && json.Unmarshal(data, &kubectlArgs) == nil {
+			inv := kubectlCommand(kubectlArgs)
+			req.Verb, req.Resource, req.Namespace = inv.command, inv.resource, inv.namespace
+			flagArgs := make(map[string]interface{}, len(inv.flags))
+			for k, v := range inv.flags {
+				flagArgs[k] = v
+			}
+			req.Args["flags"] = flagArgs
+			if inv.subcommand != "" {
+				req.Args["subcommand"] = inv.subcommand
+			}
+			allNamespaces = inv.allNamespaces
+			if inv.unknownResource {
+				req.Unknown = append(req.Unknown, "resource")
+			}
+			if inv.unknownNamespace {
+				req.Unknown = append(req.Unknown, "namespace")
+				return req
+			}
+		}
+	}
+
+	if req.Namespace == "" && !allNamespaces {
+		req.Namespace = defaultNamespace
+	}
+	return req
+}
+
+// enforcePolicy wraps a tool handler so calls the policy denies are refused
+// and calls that need confirmation only run once confirmed.
+func (h *Handler) enforcePolicy(toolName string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if h.policy == nil {
+		return handler
+	}
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		policyReq := PolicyRequest(toolName, req.GetArguments(), h.defaultNamespace)
+		policyReq.Caller = h.caller
+		policyReq.Context = h.kubeContext
+
+		decision := h.policy.Evaluate(policyReq)
+		switch decision.Effect {
+		case policy.EffectDeny:
+			log.Info().Str("tool", toolName).Str("rule", decision.Rule).Msg("Tool call denied by policy")
+			return mcp.NewToolResultError(policyMessage("denied by policy", decision)), nil
+		case policy.EffectConfirm:
+			log.Info().Str("tool", toolName).Str("rule", decision.Rule).Msg("Tool call requires confirmation")
+			action := h.destructiveAction(toolName, req, handler)
+			if action == nil {
+				args, _ := json.Marshal(withoutArgument(req, confirmTokenArg).GetArguments())
+				action = &confirmAction{command: fmt.Sprintf("%s %s", toolName, args)}
+			}
+			action.reason = policyMessage("requires confirmation by policy", decision)
+			return h.confirm(ctx, toolName, req, action, handler)
+		}
+		return handler(ctx, req)
+	}
+}
+
+func policyMessage(prefix string, decision policy.Decision) string {
+	msg := prefix
+	if decision.Rule != "" {
+		msg += fmt.Sprintf(" rule %q", decision.Rule)
+	}
+	if decision.Message != "" {
+		msg += ": " + decision.Message
+	}
+	return msg
+}
+
+// loadCaller looks up the user and groups of the current identity so policy
+// rules can match on them.
+func (h *Handler) loadCaller() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	review, err := h.client.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err != nil {
+		log.Warn().Err(err).Msg("Failed to look up the current identity, policy rules will see an empty caller")
+		return
+	}
+	h.caller = policy.Caller{
+		User:   review.Status.UserInfo.Username,
+		Groups: review.Status.UserInfo.Groups,
+	}
+}
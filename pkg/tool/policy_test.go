@@ -0,0 +1,205 @@
+package tool
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPolicyRequest(t *testing.T) {
+	tests := []struct {
+		name          string
+		tool          string
+		args          map[string]interface{}
+		wantVerb      string
+		wantResource  string
+		wantNamespace string
+		wantFlags     map[string]interface{}
+		wantUnknown   []string
+	}{
+		{
+			name:          "kubectl tool with resource argument",
+			tool:          "kubectl_delete",
+			args:          map[string]interface{}{"resource": "pod", "name": "web", "namespace": "prod-eu"},
+			wantVerb:      "delete",
+			wantResource:  "pod",
+			wantNamespace: "prod-eu",
+		},
+		{
+			name:          "permissions give verb and resource",
+			tool:          "create_job_from_cronjob",
+			args:          map[string]interface{}{"cronjob": "backup"},
+			wantVerb:      "create",
+			wantResource:  "jobs",
+			wantNamespace: "default",
+		},
+		{
+			name:         "all namespaces",
+			tool:         "kubectl_get",
+			args:         map[string]interface{}{"resource": "pods", "all_namespaces": true},
+			wantVerb:     "list",
+			wantResource: "pods",
+		},
+		{
+			name:          "generic string args",
+			tool:          "kubectl_generic",
+			args:          map[string]interface{}{"args": "scale deployment/web -n shop --replicas=20"},
+			wantVerb:      "scale",
+			wantResource:  "deployment",
+			wantNamespace: "shop",
+			wantFlags:     map[string]interface{}{"n": "shop", "replicas": "20"},
+		},
+		{
+			name:          "generic exec targets pods",
+			tool:          "kubectl_generic",
+			args:          map[string]interface{}{"args": []interface{}{"exec", "web", "--", "rm", "-rf", "/data"}},
+			wantVerb:      "exec",
+			wantResource:  "pods",
+			wantNamespace: "default",
+			wantFlags:     map[string]interface{}{},
+		},
+		{
+			name:         "generic all namespaces",
+			tool:         "kubectl_generic",
+			args:         map[string]interface{}{"args": "get pods -A"},
+			wantVerb:     "get",
+			wantResource: "pods",
+			wantFlags:    map[string]interface{}{"A": "true"},
+		},
+		{
+			name:          "generic value flag before resource",
+			tool:          "kubectl_generic",
+			args:          map[string]interface{}{"args": "delete --grace-period 0 secrets db"},
+			wantVerb:      "delete",
+			wantResource:  "secrets",
+			wantNamespace: "default",
+			wantFlags:     map[string]interface{}{"grace-period": "0"},
+		},
+		{
+			name:          "generic selector before resource",
+			tool:          "kubectl_generic",
+			args:          map[string]interface{}{"args": "delete -l app=web pods"},
+			wantVerb:      "delete",
+			wantResource:  "pods",
+			wantNamespace: "default",
+			wantFlags:     map[string]interface{}{"l": "app=web"},
+		},
+		{
+			name:          "generic attached namespace",
+			tool:          "kubectl_generic",
+			args:          map[string]interface{}{"args": "delete pods web -nprod-eu"},
+			wantVerb:      "delete",
+			wantResource:  "pods",
+			wantNamespace: "prod-eu",
+		},
+		{
+			name:          "generic namespace with equals",
+			tool:          "kubectl_generic",
+			args:          map[string]interface{}{"args": "delete pods web -n=prod"},
+			wantVerb:      "delete",
+			wantResource:  "pods",
+			wantNamespace: "prod",
+		},
+		{
+			name:          "generic logs boolean flags",
+			tool:          "kubectl_generic",
+			args:          map[string]interface{}{"args": "logs -p -n prod web"},
+			wantVerb:      "logs",
+			wantResource:  "pods",
+			wantNamespace: "prod",
+		},
+		{
+			name:          "generic combined short flags",
+			tool:          "kubectl_generic",
+			args:          map[string]interface{}{"args": []interface{}{"exec", "-it", "-nprod", "web", "--", "sh"}},
+			wantVerb:      "exec",
+			wantResource:  "pods",
+			wantNamespace: "prod",
+		},
+		{
+			name:          "generic nested subcommand",
+			tool:          "kubectl_generic",
+			args:          map[string]interface{}{"args": "rollout restart deployment/web -n shop"},
+			wantVerb:      "rollout",
+			wantResource:  "deployment",
+			wantNamespace: "shop",
+		},
+		{
+			name:          "generic unknown flag before resource",
+			tool:          "kubectl_generic",
+			args:          map[string]interface{}{"args": "delete --frobnicate secrets db"},
+			wantVerb:      "delete",
+			wantNamespace: "default",
+			wantUnknown:   []string{"resource"},
+		},
+		{
+			name:         "generic unknown flag before namespace",
+			tool:         "kubectl_generic",
+			args:         map[string]interface{}{"args": "get secrets --frobnicate -n dev"},
+			wantVerb:     "get",
+			wantResource: "secrets",
+			wantUnknown:  []string{"namespace"},
+		},
+		{
+			name:        "generic unknown short flag",
+			tool:        "kubectl_generic",
+			args:        map[string]interface{}{"args": "delete -xnprod secrets db"},
+			wantVerb:    "delete",
+			wantUnknown: []string{"resource", "namespace"},
+		},
+		{
+			name:         "generic conflicting namespaces",
+			tool:         "kubectl_generic",
+			args:         map[string]interface{}{"args": "delete pods web --namespace dev -n prod"},
+			wantVerb:     "delete",
+			wantResource: "pods",
+			wantUnknown:  []string{"namespace"},
+		},
+		{
+			name:          "generic resources from a file",
+			tool:          "kubectl_generic",
+			args:          map[string]interface{}{"args": "delete -f manifest.yaml"},
+			wantVerb:      "delete",
+			wantNamespace: "default",
+			wantUnknown:   []string{"resource"},
+		},
+		{
+			name:          "generic several resource types",
+			tool:          "kubectl_generic",
+			args:          map[string]interface{}{"args": "delete pod/web secret/db"},
+			wantVerb:      "delete",
+			wantNamespace: "default",
+			wantUnknown:   []string{"resource"},
+		},
+		{
+			name:          "generic resource list",
+			tool:          "kubectl_generic",
+			args:          map[string]interface{}{"args": "delete configmaps,secrets --all"},
+			wantVerb:      "delete",
+			wantNamespace: "default",
+			wantUnknown:   []string{"resource"},
+		},
+		{
+			name:          "unknown tool",
+			tool:          "discovery",
+			wantNamespace: "default",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PolicyRequest(tt.tool, tt.args, "default")
+			if got.Tool != tt.tool || got.Verb != tt.wantVerb || got.Resource != tt.wantResource || got.Namespace != tt.wantNamespace {
+				t.Errorf("PolicyRequest() = tool %q verb %q resource %q namespace %q, want verb %q resource %q namespace %q",
+					got.Tool, got.Verb, got.Resource, got.Namespace, tt.wantVerb, tt.wantResource, tt.wantNamespace)
+			}
+			if !reflect.DeepEqual(got.Unknown, tt.wantUnknown) {
+				t.Errorf("unknown = %v, want %v", got.Unknown, tt.wantUnknown)
+			}
+			if flags, _ := got.Args["flags"].(map[string]interface{}); tt.wantFlags != nil && !reflect.DeepEqual(flags, tt.wantFlags) {
+				t.Errorf("flags = %v, want %v", flags, tt.wantFlags)
+			}
+			if _, ok := tt.args["flags"]; ok {
+				t.Error("PolicyRequest() modified the call arguments")
+			}
+		})
+	}
+}
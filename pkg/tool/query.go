@@ -0,0 +1,71 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/itchyny/gojq"
+)
+
+// queryTimeout bounds the evaluation of a jq query, which can loop forever.
+const queryTimeout = 10 * time.Second
+
+// runQuery evaluates the jq query over the input, which must consist of the
+// types encoding/json decodes to. A single result is returned as is, several
+// results as a list.
+func runQuery(ctx context.Context, query string, input interface{}) (interface{}, error) {
+	parsed, err := gojq.Parse(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+	code, err := gojq.Compile(parsed)
+	if err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queryTimeout)
+	defer cancel()
+
+	results := []interface{}{}
+	iter := code.RunWithContext(ctx, input)
+	for {
+		v, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := v.(error); ok {
+			var haltErr *gojq.HaltError
+			if errors.As(err, &haltErr) && haltErr.Value() == nil {
+				break
+			}
+			return nil, fmt.Errorf("query failed: %w", err)
+		}
+		results = append(results, v)
+	}
+
+	if len(results) == 1 {
+		return results[0], nil
+	}
+	return results, nil
+}
+
+// queryJSON evaluates the jq query over a JSON document and returns the result
+// both decoded and encoded as JSON.
+func queryJSON(ctx context.Context, query string, input []byte) (interface{}, string, error) {
+	var decoded interface{}
+	if err := json.Unmarshal(input, &decoded); err != nil {
+		return nil, "", fmt.Errorf("output is not JSON: %w", err)
+	}
+	result, err := runQuery(ctx, query, decoded)
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to encode query result: %w", err)
+	}
+	return result, string(data), nil
+}
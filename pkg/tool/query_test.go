@@ -0,0 +1,69 @@
+package tool
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestQueryJSON(t *testing.T) {
+	const pods = `{"items":[
+		{"metadata":{"name":"web"},"spec":{"nodeName":"node-1"},"status":{"phase":"Running"}},
+		{"metadata":{"name":"job"},"spec":{"nodeName":"node-1"},"status":{"phase":"Failed"}},
+		{"metadata":{"name":"db"},"spec":{"nodeName":"node-2"},"status":{"phase":"Pending"}}]}`
+
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "select",
+			query: `[.items[] | select(.status.phase != "Running") | .metadata.name]`,
+			want:  `["job","db"]`,
+		},
+		{
+			name:  "aggregate",
+			query: `.items | group_by(.spec.nodeName) | map({node: .[0].spec.nodeName, pods: length})`,
+			want:  `[{"node":"node-1","pods":2},{"node":"node-2","pods":1}]`,
+		},
+		{
+			name:  "several results",
+			query: `.items[].metadata.name`,
+			want:  `["web","job","db"]`,
+		},
+		{
+			name:  "no results",
+			query: `.items[] | select(.status.phase == "Unknown")`,
+			want:  `[]`,
+		},
+		{
+			name:    "invalid",
+			query:   `.items[`,
+			wantErr: "invalid query",
+		},
+		{
+			name:    "runtime error",
+			query:   `.items | keys | .[0] + "x"`,
+			wantErr: "query failed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, got, err := queryJSON(context.Background(), tt.query, []byte(pods))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("queryJSON() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("queryJSON() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("queryJSON() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// defaultMaxConcurrentKubectl caps the kubectl processes running at once.
+	defaultMaxConcurrentKubectl = 8
+	// kubectlQueueTimeout is how long a call waits for a kubectl slot before
+	// it fails.
+	kubectlQueueTimeout = 5 * time.Second
+	// maxSessionLimiters is the number of per-session buckets kept before idle
+	// ones are dropped.
+	maxSessionLimiters = 1024
+)
+
+// RateLimit is a token bucket allowing Rate calls per second on average with
+// bursts of up to Burst calls. A zero rate disables the limit; a zero burst
+// defaults to the rate rounded up.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l RateLimit) limiter() *rate.Limiter {
+	if l.Rate <= 0 {
+		return nil
+	}
+	burst := l.Burst
+	if burst <= 0 {
+		burst = int(math.Ceil(l.Rate))
+	}
+	return rate.NewLimiter(rate.Limit(l.Rate), burst)
+}
+
+// RateLimits are the limits tool calls are checked against. A call must fit
+// in the global bucket, the bucket of its session and the bucket of its tool.
+type RateLimits struct {
+	Global     RateLimit
+	PerSession RateLimit
+	Tools      map[string]RateLimit
+}
+
+// WithRateLimits limits how often tools can be called.
+func WithRateLimits(limits RateLimits) Option {
+	return func(h *Handler) {
+		h.rateLimiter = newRateLimiter(limits)
+	}
+}
+
+// WithMaxConcurrentKubectl caps the number of kubectl processes running at
+// once. Zero removes the cap.
+func WithMaxConcurrentKubectl(max int) Option {
+	return func(h *Handler) {
+		if max >= 0 {
+			h.maxConcurrentKubectl = max
+		}
+	}
+}
+
+type rateLimiter struct {
+	global     *rate.Limiter
+	perSession RateLimit
+	tools      map[string]*rate.Limiter
+	now        func() time.Time
+
+	mu       sync.Mutex
+	sessions map[string]*rate.Limiter
+}
+
+// newRateLimiter returns a limiter for the limits, or nil when none is set.
+func newRateLimiter(limits RateLimits) *rateLimiter {
+	l := &rateLimiter{
+		global:     limits.Global.limiter(),
+		perSession: limits.PerSession,
+		tools:      map[string]*rate.Limiter{},
+		now:        time.Now,
+		sessions:   map[string]*rate.Limiter{},
+	}
+	for name, limit := range limits.Tools {
+		if limiter := limit.limiter(); limiter != nil {
+			l.tools[name] = limiter
+		}
+	}
+	if l.global == nil && l.perSession.Rate <= 0 && len(l.tools) == 0 {
+		return nil
+	}
+	return l
+}
+
+// allow takes a token from each bucket the call counts against. When any
+// bucket is empty no token is taken and allow returns which limit was hit and
+// how long until the call would fit.
+func (l *rateLimiter) allow(session, toolName string) (string, time.Duration) {
+	now := l.now()
+	type bucket struct {
+		name    string
+		limiter *rate.Limiter
+	}
+	var buckets []bucket
+	if l.global != nil {
+		buckets = append(buckets, bucket{"global", l.global})
+	}
+	if limiter := l.sessionLimiter(session, now); limiter != nil {
+		buckets = append(buckets, bucket{"session", limiter})
+	}
+	if limiter := l.tools[toolName]; limiter != nil {
+		buckets = append(buckets, bucket{"tool " + toolName, limiter})
+	}
+
+	var reservations []*rate.Reservation
+	var exceeded []string
+	var retryAfter time.Duration
+	for _, b := range buckets {
+		r := b.limiter.ReserveN(now, 1)
+		reservations = append(reservations, r)
+		delay := r.DelayFrom(now)
+		if !r.OK() {
+			delay = rate.InfDuration
+		}
+		if delay > 0 {
+			exceeded = append(exceeded, b.name)
+			if delay > retryAfter {
+				retryAfter = delay
+			}
+		}
+	}
+	if len(exceeded) == 0 {
+		return "", 0
+	}
+	for _, r := range reservations {
+		r.CancelAt(now)
+	}
+	return strings.Join(exceeded, ", "), retryAfter
+}
+
+// sessionLimiter returns the bucket of the session, dropping buckets that are
+// full again, and so equivalent to new ones, when there are too many.
+func (l *rateLimiter) sessionLimiter(session string, now time.Time) *rate.Limiter {
+	if l.perSession.Rate <= 0 {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if limiter, ok := l.sessions[session]; ok {
+		return limiter
+	}
+	if len(l.sessions) >= maxSessionLimiters {
+		for id, limiter := range l.sessions {
+			if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+				delete(l.sessions, id)
+			}
+		}
+	}
+	limiter := l.perSession.limiter()
+	l.sessions[session] = limiter
+	return limiter
+}
+
+// limitRate wraps a tool handler so calls over the rate limits fail with a
+// hint on when to retry instead of running.
+func (h *Handler) limitRate(toolName string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if h.rateLimiter == nil {
+		return handler
+	}
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		session := ""
+		if s := server.ClientSessionFromContext(ctx); s != nil {
+			session = s.SessionID()
+		}
+		if exceeded, retryAfter := h.rateLimiter.allow(session, toolName); exceeded != "" {
+			log.Warn().Str("tool", toolName).Str("session", session).Str("limit", exceeded).Msg("Tool call rate limited")
+			return mcp.NewToolResultError(fmt.Sprintf("rate limit exceeded (%s): %s was not run, retry after %s",
+				exceeded, toolName, retryAfterHint(retryAfter))), nil
+		}
+		return handler(ctx, req)
+	}
+}
+
+func retryAfterHint(d time.Duration) string {
+	if d == rate.InfDuration {
+		return "the limit is raised"
+	}
+	return d.Round(100 * time.Millisecond).String()
+}
+
+// acquireKubectl waits for a free kubectl slot and returns the function that
+// releases it. It fails instead of queueing for longer than
+// kubectlQueueTimeout.
+func (h *Handler) acquireKubectl(ctx context.Context) (func(), error) {
+	if h.kubectlSlots == nil {
+		return func() {}, nil
+	}
+	timer := time.NewTimer(kubectlQueueTimeout)
+	defer timer.Stop()
+	select {
+	case h.kubectlSlots <- struct{}{}:
+		return func() { <-h.kubectlSlots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+		return nil, fmt.Errorf("%d kubectl commands are already running, retry after a few seconds", cap(h.kubectlSlots))
+	}
+}
@@ -0,0 +1,102 @@
+package tool
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+func TestRateLimiterAllow(t *testing.T) {
+	l := newRateLimiter(RateLimits{
+		Global:     RateLimit{Rate: 10, Burst: 4},
+		PerSession: RateLimit{Rate: 1, Burst: 2},
+		Tools:      map[string]RateLimit{"kubectl_get": {Rate: 0.5}},
+	})
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	l.now = func() time.Time { return now }
+
+	steps := []struct {
+		session      string
+		tool         string
+		wantExceeded string
+	}{
+		{"a", "kubectl_get", ""},
+		{"a", "kubectl_get", "tool kubectl_get"},
+		{"a", "count_pods", ""},
+		{"a", "count_pods", "session"},
+		{"b", "count_pods", ""},
+		{"b", "count_pods", ""},
+		{"c", "count_pods", "global"},
+	}
+	for i, step := range steps {
+		exceeded, retryAfter := l.allow(step.session, step.tool)
+		if exceeded != step.wantExceeded {
+			t.Fatalf("step %d: allow(%q, %q) exceeded %q, want %q", i+1, step.session, step.tool, exceeded, step.wantExceeded)
+		}
+		if (exceeded != "") != (retryAfter > 0) {
+			t.Errorf("step %d: retry after %s for exceeded %q", i+1, retryAfter, exceeded)
+		}
+	}
+
+	// Rejected calls don't use up tokens, so the global bucket refills first.
+	now = now.Add(100 * time.Millisecond)
+	if exceeded, _ := l.allow("c", "count_pods"); exceeded != "" {
+		t.Errorf("allow() after refill exceeded %q", exceeded)
+	}
+	now = now.Add(2 * time.Second)
+	if exceeded, _ := l.allow("a", "kubectl_get"); exceeded != "" {
+		t.Errorf("allow() after refill exceeded %q", exceeded)
+	}
+}
+
+func TestNewRateLimiterDisabled(t *testing.T) {
+	if l := newRateLimiter(RateLimits{Tools: map[string]RateLimit{"kubectl_get": {}}}); l != nil {
+		t.Error("newRateLimiter() without rates returned a limiter")
+	}
+}
+
+func TestLimitRate(t *testing.T) {
+	h := &Handler{rateLimiter: newRateLimiter(RateLimits{Global: RateLimit{Rate: 1}})}
+	calls := 0
+	handler := h.limitRate("count_pods", func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	for i := 0; i < 2; i++ {
+		result, err := handler(context.Background(), mcp.CallToolRequest{})
+		if err != nil {
+			t.Fatalf("handler returned error: %v", err)
+		}
+		if i == 1 && (!result.IsError || !strings.Contains(resultText(result), "retry after")) {
+			t.Errorf("throttled call = %q, want a rate limit error with a retry hint", resultText(result))
+		}
+	}
+	if calls != 1 {
+		t.Errorf("handler ran %d times, want 1", calls)
+	}
+}
+
+func TestAcquireKubectl(t *testing.T) {
+	h := &Handler{kubectlSlots: make(chan struct{}, 1)}
+	release, err := h.acquireKubectl(context.Background())
+	if err != nil {
+		t.Fatalf("acquireKubectl() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := h.acquireKubectl(ctx); err == nil {
+		t.Error("acquireKubectl() succeeded with all slots taken")
+	}
+
+	release()
+	release, err = h.acquireKubectl(context.Background())
+	if err != nil {
+		t.Fatalf("acquireKubectl() after release error = %v", err)
+	}
+	release()
+}
@@ -0,0 +1,480 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+func (h *Handler) registerRBAC(m *server.MCPServer) {
+	h.addTool(m, mcp.NewTool("rbac_who_can",
+		mcp.WithDescription("Find which users, groups and service accounts are granted a verb on a resource by RBAC, explaining the binding and role chain behind each grant (aggregated ClusterRoles included)"),
+		mcp.WithString("verb",
+			mcp.Description("Verb to check (e.g., get, list, delete, create, patch)"),
+			mcp.Required(),
+		),
+		mcp.WithString("resource",
+			mcp.Description("Resource type, optionally with subresource (e.g., secrets, deployments.apps, pods/exec)"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to check; leave empty to only consider cluster-wide grants from ClusterRoleBindings"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of a specific resource, to include grants restricted by resourceNames"),
+		),
+		mcp.WithOutputSchema[rbacWhoCanResult](),
+	), mcp.NewTypedToolHandler[RBACWhoCanArgs](h.rbacWhoCanHandler()))
+
+	h.addTool(m, mcp.NewTool("rbac_subject_permissions",
+		mcp.WithDescription("List everything a user, group or service account is granted by RBAC across the cluster, explaining the binding and role chain behind each rule"),
+		mcp.WithString("kind",
+			mcp.Description("Subject kind"),
+			mcp.Enum(rbacv1.UserKind, rbacv1.GroupKind, rbacv1.ServiceAccountKind),
+			mcp.Required(),
+		),
+		mcp.WithString("name",
+			mcp.Description("Subject name (user name, group name or service account name)"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the service account (required for kind ServiceAccount)"),
+		),
+		mcp.WithOutputSchema[rbacSubjectPermissionsResult](),
+	), mcp.NewTypedToolHandler[RBACSubjectPermissionsArgs](h.rbacSubjectPermissionsHandler()))
+}
+
+type RBACWhoCanArgs struct {
+	Verb      string `json:"verb"`
+	Resource  string `json:"resource"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name,omitempty"`
+}
+
+func (h *Handler) rbacWhoCanHandler() mcp.TypedToolHandlerFunc[RBACWhoCanArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args RBACWhoCanArgs,
+	) (*mcp.CallToolResult, error) {
+		resource, subresource, _ := strings.Cut(args.Resource, "/")
+		group := ""
+		if resource != "*" {
+			mapping, err := h.resolveResource(resource)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to resolve resource", err), nil
+			}
+			group, resource = mapping.Resource.Group, mapping.Resource.Resource
+		}
+		if subresource != "" {
+			resource += "/" + subresource
+		}
+
+		snapshot, err := h.loadRBAC(ctx, args.Namespace)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to load RBAC objects", err), nil
+		}
+
+		grants := snapshot.whoCan(args.Verb, group, resource, args.Namespace, args.Name)
+
+		scope := "cluster-wide"
+		if args.Namespace != "" {
+			scope = "in namespace " + args.Namespace
+		}
+		target := resource
+		if group != "" {
+			target += "." + group
+		}
+		if args.Name != "" {
+			target += "/" + args.Name
+		}
+
+		structured := rbacWhoCanResult{Verb: args.Verb, Target: target, Scope: scope, Subjects: countSubjects(grants), Grants: grants}
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("Subjects that can %s %s %s: %d\n", args.Verb, target, scope, structured.Subjects))
+		writeGrants(&sb, grants, true)
+		return mcp.NewToolResultStructured(structured, sb.String()), nil
+	}
+}
+
+type RBACSubjectPermissionsArgs struct {
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+func (h *Handler) rbacSubjectPermissionsHandler() mcp.TypedToolHandlerFunc[RBACSubjectPermissionsArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args RBACSubjectPermissionsArgs,
+	) (*mcp.CallToolResult, error) {
+		subject := rbacv1.Subject{Kind: args.Kind, Name: args.Name}
+		switch args.Kind {
+		case rbacv1.ServiceAccountKind:
+			if args.Namespace == "" {
+				return mcp.NewToolResultError("namespace is required for kind ServiceAccount"), nil
+			}
+			subject.Namespace = args.Namespace
+		case rbacv1.UserKind, rbacv1.GroupKind:
+			subject.APIGroup = rbacv1.GroupName
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf("unsupported subject kind '%s' (expected User, Group or ServiceAccount)", args.Kind)), nil
+		}
+
+		snapshot, err := h.loadRBAC(ctx, metav1.NamespaceAll)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to load RBAC objects", err), nil
+		}
+
+		grants := snapshot.subjectGrants(subject)
+
+		structured := rbacSubjectPermissionsResult{Subject: subject, Grants: grants}
+		switch subject.Kind {
+		case rbacv1.ServiceAccountKind:
+			structured.Note = "Includes grants to the implicit groups system:serviceaccounts, system:serviceaccounts:" + subject.Namespace + " and system:authenticated."
+		case rbacv1.UserKind:
+			structured.Note = "Grants through group membership are not included; query the user's groups separately."
+		}
+		var sb strings.Builder
+		sb.WriteString(fmt.Sprintf("RBAC rules granted to %s: %d\n", formatSubject(subject), len(grants)))
+		if structured.Note != "" {
+			sb.WriteString(structured.Note + "\n")
+		}
+		writeGrants(&sb, grants, false)
+		return mcp.NewToolResultStructured(structured, sb.String()), nil
+	}
+}
+
+// rbacSnapshot holds the RBAC objects needed to resolve grants.
+type rbacSnapshot struct {
+	clusterRoles        map[string]*rbacv1.ClusterRole
+	roles               map[string]*rbacv1.Role
+	clusterRoleBindings []rbacv1.ClusterRoleBinding
+	roleBindings        []rbacv1.RoleBinding
+}
+
+// rbacGrant is a single rule granted to a subject through a binding.
+type rbacGrant struct {
+	Subject rbacv1.Subject    `json:"subject"`
+	Scope   string            `json:"scope"`
+	Chain   []string          `json:"chain"`
+	Rule    rbacv1.PolicyRule `json:"rule"`
+}
+
+// rbacWhoCanResult is the structured output of rbac_who_can.
+type rbacWhoCanResult struct {
+	Verb     string      `json:"verb"`
+	Target   string      `json:"target"`
+	Scope    string      `json:"scope"`
+	Subjects int         `json:"subjects"`
+	Grants   []rbacGrant `json:"grants"`
+}
+
+// rbacSubjectPermissionsResult is the structured output of
+// rbac_subject_permissions.
+type rbacSubjectPermissionsResult struct {
+	Subject rbacv1.Subject `json:"subject"`
+	Note    string         `json:"note,omitempty"`
+	Grants  []rbacGrant    `json:"grants"`
+}
+
+// sourcedRule is a policy rule together with the role chain it came from.
+type sourcedRule struct {
+	rule  rbacv1.PolicyRule
+	chain []string
+}
+
+// loadRBAC loads all ClusterRoles and ClusterRoleBindings, plus the Roles and
+// RoleBindings in the namespace (or in all namespaces when it is empty).
+func (h *Handler) loadRBAC(ctx context.Context, namespace string) (*rbacSnapshot, error) {
+	clusterRoles, err := h.client.RbacV1().ClusterRoles().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusterroles: %w", err)
+	}
+	clusterRoleBindings, err := h.client.RbacV1().ClusterRoleBindings().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clusterrolebindings: %w", err)
+	}
+	roles, err := h.client.RbacV1().Roles(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list roles: %w", err)
+	}
+	roleBindings, err := h.client.RbacV1().RoleBindings(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list rolebindings: %w", err)
+	}
+
+	snapshot := &rbacSnapshot{
+		clusterRoles:        make(map[string]*rbacv1.ClusterRole, len(clusterRoles.Items)),
+		roles:               make(map[string]*rbacv1.Role, len(roles.Items)),
+		clusterRoleBindings: clusterRoleBindings.Items,
+		roleBindings:        roleBindings.Items,
+	}
+	for i := range clusterRoles.Items {
+		snapshot.clusterRoles[clusterRoles.Items[i].Name] = &clusterRoles.Items[i]
+	}
+	for i := range roles.Items {
+		snapshot.roles[roles.Items[i].Namespace+"/"+roles.Items[i].Name] = &roles.Items[i]
+	}
+	return snapshot, nil
+}
+
+// clusterRoleRules returns the rules of a ClusterRole. For aggregated roles the
+// rules are collected from the ClusterRoles matched by the aggregation
+// selectors, so the chain shows where each rule originates.
+func (s *rbacSnapshot) clusterRoleRules(name string, visited map[string]bool) []sourcedRule {
+	role, ok := s.clusterRoles[name]
+	if !ok || visited[name] {
+		return nil
+	}
+	visited[name] = true
+
+	link := "ClusterRole " + name
+	if role.AggregationRule == nil || len(role.AggregationRule.ClusterRoleSelectors) == 0 {
+		rules := make([]sourcedRule, 0, len(role.Rules))
+		for _, r := range role.Rules {
+			rules = append(rules, sourcedRule{rule: r, chain: []string{link}})
+		}
+		return rules
+	}
+
+	var rules []sourcedRule
+	names := make([]string, 0, len(s.clusterRoles))
+	for n := range s.clusterRoles {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, sel := range role.AggregationRule.ClusterRoleSelectors {
+		selector, err := metav1.LabelSelectorAsSelector(&sel)
+		if err != nil || selector.Empty() {
+			continue
+		}
+		for _, n := range names {
+			if n == name || !selector.Matches(labels.Set(s.clusterRoles[n].Labels)) {
+				continue
+			}
+			for _, r := range s.clusterRoleRules(n, visited) {
+				r.chain = append([]string{link + " (aggregates)"}, r.chain...)
+				rules = append(rules, r)
+			}
+		}
+	}
+	return rules
+}
+
+// roleRefRules returns the rules referenced by a binding's roleRef.
+func (s *rbacSnapshot) roleRefRules(ref rbacv1.RoleRef, namespace string) []sourcedRule {
+	switch ref.Kind {
+	case "ClusterRole":
+		return s.clusterRoleRules(ref.Name, map[string]bool{})
+	case "Role":
+		role, ok := s.roles[namespace+"/"+ref.Name]
+		if !ok {
+			return nil
+		}
+		rules := make([]sourcedRule, 0, len(role.Rules))
+		for _, r := range role.Rules {
+			rules = append(rules, sourcedRule{rule: r, chain: []string{fmt.Sprintf("Role %s/%s", namespace, ref.Name)}})
+		}
+		return rules
+	default:
+		return nil
+	}
+}
+
+// whoCan resolves the grants that allow the verb on the resource. Cluster-wide
+// grants always apply; RoleBindings only when a namespace is given.
+func (s *rbacSnapshot) whoCan(verb, group, resource, namespace, name string) []rbacGrant {
+	var grants []rbacGrant
+	for _, b := range s.clusterRoleBindings {
+		for _, r := range s.roleRefRules(b.RoleRef, "") {
+			if !policyRuleAllows(r.rule, verb, group, resource, name) {
+				continue
+			}
+			chain := append([]string{"ClusterRoleBinding " + b.Name}, r.chain...)
+			for _, subject := range b.Subjects {
+				grants = append(grants, rbacGrant{Subject: subject, Scope: "cluster-wide", Chain: chain, Rule: r.rule})
+			}
+		}
+	}
+	if namespace == "" {
+		return grants
+	}
+	for _, b := range s.roleBindings {
+		if b.Namespace != namespace {
+			continue
+		}
+		for _, r := range s.roleRefRules(b.RoleRef, b.Namespace) {
+			if !policyRuleAllows(r.rule, verb, group, resource, name) {
+				continue
+			}
+			chain := append([]string{fmt.Sprintf("RoleBinding %s/%s", b.Namespace, b.Name)}, r.chain...)
+			for _, subject := range b.Subjects {
+				// Service accounts in a RoleBinding default to the binding's namespace.
+				if subject.Kind == rbacv1.ServiceAccountKind && subject.Namespace == "" {
+					subject.Namespace = b.Namespace
+				}
+				grants = append(grants, rbacGrant{Subject: subject, Scope: "namespace " + b.Namespace, Chain: chain, Rule: r.rule})
+			}
+		}
+	}
+	return grants
+}
+
+// subjectGrants returns every rule bound to the subject, including the
+// implicit groups of service accounts.
+func (s *rbacSnapshot) subjectGrants(subject rbacv1.Subject) []rbacGrant {
+	var grants []rbacGrant
+	for _, b := range s.clusterRoleBindings {
+		matched, ok := matchingSubject(b.Subjects, subject, "")
+		if !ok {
+			continue
+		}
+		for _, r := range s.roleRefRules(b.RoleRef, "") {
+			chain := append([]string{"ClusterRoleBinding " + b.Name}, r.chain...)
+			grants = append(grants, rbacGrant{Subject: matched, Scope: "cluster-wide", Chain: chain, Rule: r.rule})
+		}
+	}
+	for _, b := range s.roleBindings {
+		matched, ok := matchingSubject(b.Subjects, subject, b.Namespace)
+		if !ok {
+			continue
+		}
+		for _, r := range s.roleRefRules(b.RoleRef, b.Namespace) {
+			chain := append([]string{fmt.Sprintf("RoleBinding %s/%s", b.Namespace, b.Name)}, r.chain...)
+			grants = append(grants, rbacGrant{Subject: matched, Scope: "namespace " + b.Namespace, Chain: chain, Rule: r.rule})
+		}
+	}
+	return grants
+}
+
+// matchingSubject returns the binding subject that applies to the target,
+// either directly or through an implicit service account group.
+func matchingSubject(subjects []rbacv1.Subject, target rbacv1.Subject, bindingNamespace string) (rbacv1.Subject, bool) {
+	var groups []string
+	if target.Kind == rbacv1.ServiceAccountKind {
+		groups = []string{"system:serviceaccounts", "system:serviceaccounts:" + target.Namespace, "system:authenticated"}
+	}
+
+	for _, s := range subjects {
+		switch {
+		case s.Kind != target.Kind:
+			if s.Kind == rbacv1.GroupKind && containsString(groups, s.Name) {
+				return s, true
+			}
+		case s.Kind == rbacv1.ServiceAccountKind:
+			ns := s.Namespace
+			if ns == "" {
+				ns = bindingNamespace
+			}
+			if s.Name == target.Name && ns == target.Namespace {
+				s.Namespace = ns
+				return s, true
+			}
+		case s.Name == target.Name:
+			return s, true
+		}
+	}
+	return rbacv1.Subject{}, false
+}
+
+// policyRuleAllows reports whether the rule allows the verb on the resource.
+// Rules restricted by resourceNames only match when that name is requested.
+func policyRuleAllows(rule rbacv1.PolicyRule, verb, group, resource, name string) bool {
+	if !matchesAny(rule.Verbs, verb) || !matchesAny(rule.APIGroups, group) {
+		return false
+	}
+	matched := false
+	for _, r := range rule.Resources {
+		if resourceMatches(r, resource) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return false
+	}
+	if len(rule.ResourceNames) == 0 {
+		return true
+	}
+	return name != "" && containsString(rule.ResourceNames, name)
+}
+
+func countSubjects(grants []rbacGrant) int {
+	seen := map[string]bool{}
+	for _, g := range grants {
+		seen[formatSubject(g.Subject)] = true
+	}
+	return len(seen)
+}
+
+func formatSubject(s rbacv1.Subject) string {
+	if s.Kind == rbacv1.ServiceAccountKind {
+		return fmt.Sprintf("ServiceAccount %s/%s", s.Namespace, s.Name)
+	}
+	return fmt.Sprintf("%s %s", s.Kind, s.Name)
+}
+
+func formatPolicyRule(r rbacv1.PolicyRule) string {
+	parts := []string{"verbs=" + bracketList(r.Verbs)}
+	if len(r.APIGroups) > 0 {
+		groups := make([]string, len(r.APIGroups))
+		for i, g := range r.APIGroups {
+			if g == "" {
+				g = `""`
+			}
+			groups[i] = g
+		}
+		parts = append(parts, "apiGroups="+bracketList(groups))
+	}
+	if len(r.Resources) > 0 {
+		parts = append(parts, "resources="+bracketList(r.Resources))
+	}
+	if len(r.ResourceNames) > 0 {
+		parts = append(parts, "resourceNames="+bracketList(r.ResourceNames))
+	}
+	if len(r.NonResourceURLs) > 0 {
+		parts = append(parts, "nonResourceURLs="+bracketList(r.NonResourceURLs))
+	}
+	return strings.Join(parts, " ")
+}
+
+// writeGrants writes the grants grouped by subject (who-can) or as a flat list
+// of rules (subject permissions).
+func writeGrants(sb *strings.Builder, grants []rbacGrant, groupBySubject bool) {
+	if !groupBySubject {
+		for _, g := range grants {
+			sb.WriteString(fmt.Sprintf("\n%s (%s)\n  via %s\n", formatPolicyRule(g.Rule), g.Scope, strings.Join(g.Chain, " -> ")))
+			if g.Subject.Kind == rbacv1.GroupKind {
+				sb.WriteString(fmt.Sprintf("  bound to %s\n", formatSubject(g.Subject)))
+			}
+		}
+		return
+	}
+
+	bySubject := map[string][]rbacGrant{}
+	var subjects []string
+	for _, g := range grants {
+		key := formatSubject(g.Subject)
+		if _, ok := bySubject[key]; !ok {
+			subjects = append(subjects, key)
+		}
+		bySubject[key] = append(bySubject[key], g)
+	}
+	sort.Strings(subjects)
+
+	for _, subject := range subjects {
+		sb.WriteString("\n" + subject + "\n")
+		for _, g := range bySubject[subject] {
+			sb.WriteString(fmt.Sprintf("  %s via %s\n    rule: %s\n", g.Scope, strings.Join(g.Chain, " -> "), formatPolicyRule(g.Rule)))
+		}
+	}
+}
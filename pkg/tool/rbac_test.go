@@ -0,0 +1,110 @@
+package tool
+
+import (
+	"strings"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testRBACSnapshot() *rbacSnapshot {
+	secretsReader := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "secrets-reader", Labels: map[string]string{"aggregate-to-ops": "true"}},
+		Rules:      []rbacv1.PolicyRule{{Verbs: []string{"get", "delete"}, APIGroups: []string{""}, Resources: []string{"secrets"}}},
+	}
+	ops := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "ops"},
+		AggregationRule: &rbacv1.AggregationRule{ClusterRoleSelectors: []metav1.LabelSelector{
+			{MatchLabels: map[string]string{"aggregate-to-ops": "true"}},
+		}},
+	}
+	admin := &rbacv1.ClusterRole{
+		ObjectMeta: metav1.ObjectMeta{Name: "cluster-admin"},
+		Rules:      []rbacv1.PolicyRule{{Verbs: []string{"*"}, APIGroups: []string{"*"}, Resources: []string{"*"}}},
+	}
+	named := &rbacv1.Role{
+		ObjectMeta: metav1.ObjectMeta{Name: "one-secret", Namespace: "prod"},
+		Rules:      []rbacv1.PolicyRule{{Verbs: []string{"delete"}, APIGroups: []string{""}, Resources: []string{"secrets"}, ResourceNames: []string{"db"}}},
+	}
+
+	return &rbacSnapshot{
+		clusterRoles: map[string]*rbacv1.ClusterRole{
+			secretsReader.Name: secretsReader,
+			ops.Name:           ops,
+			admin.Name:         admin,
+		},
+		roles: map[string]*rbacv1.Role{"prod/one-secret": named},
+		clusterRoleBindings: []rbacv1.ClusterRoleBinding{{
+			ObjectMeta: metav1.ObjectMeta{Name: "admins"},
+			RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "cluster-admin"},
+			Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "system:masters"}},
+		}},
+		roleBindings: []rbacv1.RoleBinding{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "ops", Namespace: "prod"},
+				RoleRef:    rbacv1.RoleRef{Kind: "ClusterRole", Name: "ops"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.UserKind, Name: "alice"}, {Kind: rbacv1.ServiceAccountKind, Name: "deployer"}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "db-cleanup", Namespace: "prod"},
+				RoleRef:    rbacv1.RoleRef{Kind: "Role", Name: "one-secret"},
+				Subjects:   []rbacv1.Subject{{Kind: rbacv1.GroupKind, Name: "system:serviceaccounts:ci"}},
+			},
+		},
+	}
+}
+
+func TestRBACWhoCan(t *testing.T) {
+	snapshot := testRBACSnapshot()
+
+	tests := []struct {
+		name      string
+		namespace string
+		resource  string
+		object    string
+		want      []string
+	}{
+		{"cluster-wide only", "", "secrets", "", []string{"Group system:masters"}},
+		{"namespace includes aggregated role", "prod", "secrets", "", []string{"Group system:masters", "User alice", "ServiceAccount prod/deployer"}},
+		{"resource names", "prod", "secrets", "db", []string{"Group system:masters", "User alice", "ServiceAccount prod/deployer", "Group system:serviceaccounts:ci"}},
+		{"other resource", "prod", "configmaps", "", []string{"Group system:masters"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			grants := snapshot.whoCan("delete", "", tt.resource, tt.namespace, tt.object)
+			var got []string
+			for _, g := range grants {
+				got = append(got, formatSubject(g.Subject))
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("whoCan() subjects = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	grants := snapshot.whoCan("delete", "", "secrets", "prod", "")
+	chain := strings.Join(grants[1].Chain, " -> ")
+	if chain != "RoleBinding prod/ops -> ClusterRole ops (aggregates) -> ClusterRole secrets-reader" {
+		t.Errorf("whoCan() chain = %q", chain)
+	}
+}
+
+func TestRBACSubjectGrants(t *testing.T) {
+	snapshot := testRBACSnapshot()
+
+	grants := snapshot.subjectGrants(rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "deployer", Namespace: "prod"})
+	if len(grants) != 1 || grants[0].Scope != "namespace prod" {
+		t.Fatalf("subjectGrants() = %+v, want a single grant in namespace prod", grants)
+	}
+
+	grants = snapshot.subjectGrants(rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "builder", Namespace: "ci"})
+	if len(grants) != 1 || grants[0].Subject.Name != "system:serviceaccounts:ci" {
+		t.Fatalf("subjectGrants() = %+v, want a grant through the implicit namespace group", grants)
+	}
+
+	grants = snapshot.subjectGrants(rbacv1.Subject{Kind: rbacv1.UserKind, Name: "bob"})
+	if len(grants) != 0 {
+		t.Fatalf("subjectGrants() = %+v, want none", grants)
+	}
+}
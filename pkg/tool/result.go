@@ -0,0 +1,17 @@
+package tool
+
+import (
+	"encoding/json"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// structuredResult returns v as structured content, with its indented JSON as
+// text for clients that don't read structured content.
+func structuredResult(v any) (*mcp.CallToolResult, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultStructured(v, string(data)), nil
+}
@@ -0,0 +1,365 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const podSecurityEnforceLabel = "pod-security.kubernetes.io/enforce"
+
+type findingSeverity string
+
+const (
+	severityHigh   findingSeverity = "high"
+	severityMedium findingSeverity = "medium"
+	severityLow    findingSeverity = "low"
+)
+
+var severityRank = map[findingSeverity]int{severityHigh: 3, severityMedium: 2, severityLow: 1}
+
+// Pod Security Standards level a check belongs to. Checks outside of the
+// standards are reported as best practices.
+const (
+	levelBaseline     = "baseline"
+	levelRestricted   = "restricted"
+	levelBestPractice = "best-practice"
+)
+
+// baselineCapabilities are the capabilities the baseline Pod Security Standard
+// allows containers to add.
+var baselineCapabilities = map[corev1.Capability]bool{
+	"AUDIT_WRITE": true, "CHOWN": true, "DAC_OVERRIDE": true, "FOWNER": true,
+	"FSETID": true, "KILL": true, "MKNOD": true, "NET_BIND_SERVICE": true,
+	"SETFCAP": true, "SETGID": true, "SETPCAP": true, "SETUID": true, "SYS_CHROOT": true,
+}
+
+type securityFinding struct {
+	Check       string          `json:"check"`
+	Severity    findingSeverity `json:"severity"`
+	Level       string          `json:"level"`
+	Object      string          `json:"object"`
+	Container   string          `json:"container,omitempty"`
+	Message     string          `json:"message"`
+	Remediation string          `json:"remediation"`
+}
+
+type securityScanResult struct {
+	Scope            string                  `json:"scope"`
+	WorkloadsScanned int                     `json:"workloadsScanned"`
+	TotalFindings    int                     `json:"totalFindings"`
+	BySeverity       map[findingSeverity]int `json:"bySeverity"`
+	Findings         []securityFinding       `json:"findings"`
+}
+
+func (h *Handler) registerSecurity(m *server.MCPServer) {
+	h.addTool(m, mcp.NewTool("security_scan",
+		mcp.WithDescription("Scan workload pod templates and namespaces for risky security settings (privileged containers, host namespaces and paths, capabilities, root users, missing limits, :latest images, automounted tokens, missing Pod Security Admission labels), aligned with the Pod Security Standards"),
+		mcp.WithOutputSchema[securityScanResult](),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to scan (leave empty to scan all namespaces)"),
+		),
+		mcp.WithString("min_severity",
+			mcp.Description("Only report findings with at least this severity"),
+			mcp.Enum(string(severityLow), string(severityMedium), string(severityHigh)),
+			mcp.DefaultString(string(severityLow)),
+		),
+	), mcp.NewTypedToolHandler[SecurityScanArgs](h.securityScanHandler()))
+}
+
+type SecurityScanArgs struct {
+	Namespace   string `json:"namespace,omitempty"`
+	MinSeverity string `json:"min_severity,omitempty"`
+}
+
+func (h *Handler) securityScanHandler() mcp.TypedToolHandlerFunc[SecurityScanArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args SecurityScanArgs,
+	) (*mcp.CallToolResult, error) {
+		minRank := severityRank[findingSeverity(args.MinSeverity)]
+
+		findings, scanned, err := h.scanWorkloads(ctx, args.Namespace)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("security scan failed", err), nil
+		}
+		nsFindings, err := h.scanNamespaces(ctx, args.Namespace)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("security scan failed", err), nil
+		}
+		findings = append(findings, nsFindings...)
+
+		filtered := make([]securityFinding, 0, len(findings))
+		counts := map[findingSeverity]int{}
+		for _, f := range findings {
+			if severityRank[f.Severity] < minRank {
+				continue
+			}
+			counts[f.Severity]++
+			filtered = append(filtered, f)
+		}
+		sort.SliceStable(filtered, func(i, j int) bool {
+			return severityRank[filtered[i].Severity] > severityRank[filtered[j].Severity]
+		})
+
+		scope := "All namespaces"
+		if args.Namespace != "" {
+			scope = fmt.Sprintf("Namespace: %s", args.Namespace)
+		}
+		result, err := structuredResult(securityScanResult{
+			Scope:            scope,
+			WorkloadsScanned: scanned,
+			TotalFindings:    len(filtered),
+			BySeverity:       counts,
+			Findings:         filtered,
+		})
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to marshal findings", err), nil
+		}
+		return result, nil
+	}
+}
+
+// scanWorkloads scans the pod templates of all workload kinds plus bare pods
+// that are not managed by a controller.
+func (h *Handler) scanWorkloads(ctx context.Context, namespace string) ([]securityFinding, int, error) {
+	var findings []securityFinding
+	scanned := 0
+	scan := func(kind string, meta metav1.ObjectMeta, spec *corev1.PodSpec) {
+		scanned++
+		findings = append(findings, scanPodSpec(fmt.Sprintf("%s %s/%s", kind, meta.Namespace, meta.Name), spec)...)
+	}
+
+	deployments, err := h.client.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list deployments: %w", err)
+	}
+	for _, d := range deployments.Items {
+		scan("Deployment", d.ObjectMeta, &d.Spec.Template.Spec)
+	}
+
+	statefulSets, err := h.client.AppsV1().StatefulSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list statefulsets: %w", err)
+	}
+	for _, s := range statefulSets.Items {
+		scan("StatefulSet", s.ObjectMeta, &s.Spec.Template.Spec)
+	}
+
+	daemonSets, err := h.client.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list daemonsets: %w", err)
+	}
+	for _, d := range daemonSets.Items {
+		scan("DaemonSet", d.ObjectMeta, &d.Spec.Template.Spec)
+	}
+
+	cronJobs, err := h.client.BatchV1().CronJobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list cronjobs: %w", err)
+	}
+	for _, c := range cronJobs.Items {
+		scan("CronJob", c.ObjectMeta, &c.Spec.JobTemplate.Spec.Template.Spec)
+	}
+
+	jobs, err := h.client.BatchV1().Jobs(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	for _, j := range jobs.Items {
+		if metav1.GetControllerOf(&j) != nil {
+			continue
+		}
+		scan("Job", j.ObjectMeta, &j.Spec.Template.Spec)
+	}
+
+	pods, err := h.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list pods: %w", err)
+	}
+	for _, p := range pods.Items {
+		if metav1.GetControllerOf(&p) != nil {
+			continue
+		}
+		scan("Pod", p.ObjectMeta, &p.Spec)
+	}
+
+	return findings, scanned, nil
+}
+
+// scanNamespaces reports namespaces that don't enforce a Pod Security Admission level.
+func (h *Handler) scanNamespaces(ctx context.Context, namespace string) ([]securityFinding, error) {
+	var namespaces []corev1.Namespace
+	if namespace != "" {
+		ns, err := h.client.CoreV1().Namespaces().Get(ctx, namespace, metav1.GetOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get namespace: %w", err)
+		}
+		namespaces = append(namespaces, *ns)
+	} else {
+		list, err := h.client.CoreV1().Namespaces().List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list namespaces: %w", err)
+		}
+		namespaces = list.Items
+	}
+
+	var findings []securityFinding
+	for _, ns := range namespaces {
+		level, ok := ns.Labels[podSecurityEnforceLabel]
+		switch {
+		case !ok:
+			findings = append(findings, securityFinding{
+				Check: "pod-security-admission", Severity: severityMedium, Level: levelBestPractice,
+				Object:      "Namespace " + ns.Name,
+				Message:     "namespace has no Pod Security Admission enforce label",
+				Remediation: fmt.Sprintf("label the namespace with %s=baseline (or restricted)", podSecurityEnforceLabel),
+			})
+		case level == "privileged":
+			findings = append(findings, securityFinding{
+				Check: "pod-security-admission", Severity: severityLow, Level: levelBestPractice,
+				Object:      "Namespace " + ns.Name,
+				Message:     "namespace enforces the privileged Pod Security level, which allows any pod",
+				Remediation: fmt.Sprintf("use %s=baseline or restricted unless the namespace runs system components", podSecurityEnforceLabel),
+			})
+		}
+	}
+	return findings, nil
+}
+
+// scanPodSpec checks a pod spec against the baseline and restricted Pod
+// Security Standards and a set of common best practices.
+func scanPodSpec(ref string, spec *corev1.PodSpec) []securityFinding {
+	var findings []securityFinding
+	add := func(check string, severity findingSeverity, level, container, message, remediation string) {
+		findings = append(findings, securityFinding{
+			Check: check, Severity: severity, Level: level, Object: ref,
+			Container: container, Message: message, Remediation: remediation,
+		})
+	}
+
+	if spec.HostNetwork {
+		add("host-namespaces", severityHigh, levelBaseline, "", "pod uses the host network namespace", "remove hostNetwork: true")
+	}
+	if spec.HostPID {
+		add("host-namespaces", severityHigh, levelBaseline, "", "pod uses the host PID namespace", "remove hostPID: true")
+	}
+	if spec.HostIPC {
+		add("host-namespaces", severityHigh, levelBaseline, "", "pod uses the host IPC namespace", "remove hostIPC: true")
+	}
+	for _, v := range spec.Volumes {
+		if v.HostPath != nil {
+			add("host-path", severityHigh, levelBaseline, "",
+				fmt.Sprintf("volume %q mounts host path %s", v.Name, v.HostPath.Path),
+				"replace the hostPath volume with a PersistentVolumeClaim, emptyDir or projected volume")
+		}
+	}
+	if spec.AutomountServiceAccountToken == nil || *spec.AutomountServiceAccountToken {
+		add("service-account-token", severityLow, levelBestPractice, "",
+			"service account token is automounted",
+			"set automountServiceAccountToken: false unless the workload talks to the Kubernetes API")
+	}
+
+	podRunAsNonRoot := spec.SecurityContext != nil && spec.SecurityContext.RunAsNonRoot != nil && *spec.SecurityContext.RunAsNonRoot
+	podRunAsRoot := spec.SecurityContext != nil && spec.SecurityContext.RunAsUser != nil && *spec.SecurityContext.RunAsUser == 0
+
+	containers := make([]corev1.Container, 0, len(spec.InitContainers)+len(spec.Containers))
+	containers = append(containers, spec.InitContainers...)
+	containers = append(containers, spec.Containers...)
+	for _, c := range containers {
+		sc := c.SecurityContext
+
+		if sc != nil && sc.Privileged != nil && *sc.Privileged {
+			add("privileged", severityHigh, levelBaseline, c.Name, "container runs privileged", "remove securityContext.privileged: true")
+		}
+
+		if sc != nil && sc.Capabilities != nil {
+			for _, capability := range sc.Capabilities.Add {
+				if !baselineCapabilities[capability] {
+					add("capabilities", severityHigh, levelBaseline, c.Name,
+						fmt.Sprintf("container adds capability %s", capability),
+						"remove the capability from securityContext.capabilities.add")
+				} else if capability != "NET_BIND_SERVICE" {
+					add("capabilities", severityMedium, levelRestricted, c.Name,
+						fmt.Sprintf("container adds capability %s", capability),
+						"only NET_BIND_SERVICE may be added under the restricted standard")
+				}
+			}
+		}
+		if sc == nil || sc.Capabilities == nil || !containsCapability(sc.Capabilities.Drop, "ALL") {
+			add("capabilities", severityLow, levelRestricted, c.Name,
+				"container does not drop all capabilities", "set securityContext.capabilities.drop: [\"ALL\"]")
+		}
+
+		if sc == nil || sc.AllowPrivilegeEscalation == nil || *sc.AllowPrivilegeEscalation {
+			add("privilege-escalation", severityMedium, levelRestricted, c.Name,
+				"container allows privilege escalation", "set securityContext.allowPrivilegeEscalation: false")
+		}
+
+		runAsRoot := podRunAsRoot
+		runAsNonRoot := podRunAsNonRoot
+		if sc != nil && sc.RunAsUser != nil {
+			runAsRoot = *sc.RunAsUser == 0
+		}
+		if sc != nil && sc.RunAsNonRoot != nil {
+			runAsNonRoot = *sc.RunAsNonRoot
+		}
+		switch {
+		case runAsRoot:
+			add("run-as-root", severityHigh, levelRestricted, c.Name, "container runs as UID 0", "set securityContext.runAsUser to a non-zero UID and runAsNonRoot: true")
+		case !runAsNonRoot:
+			add("run-as-root", severityMedium, levelRestricted, c.Name, "container may run as root (runAsNonRoot is not set)", "set securityContext.runAsNonRoot: true")
+		}
+
+		if sc == nil || sc.ReadOnlyRootFilesystem == nil || !*sc.ReadOnlyRootFilesystem {
+			add("read-only-root-filesystem", severityLow, levelBestPractice, c.Name,
+				"container root filesystem is writable",
+				"set securityContext.readOnlyRootFilesystem: true and mount emptyDir volumes for writable paths")
+		}
+
+		if c.Resources.Limits.Cpu().IsZero() || c.Resources.Limits.Memory().IsZero() {
+			add("resource-limits", severityMedium, levelBestPractice, c.Name,
+				"container has no CPU and/or memory limit", "set resources.limits.cpu and resources.limits.memory")
+		}
+
+		if tag := imageTag(c.Image); tag == "" || tag == "latest" {
+			add("image-tag", severityMedium, levelBestPractice, c.Name,
+				fmt.Sprintf("image %s uses the latest or no tag", c.Image),
+				"pin the image to a specific version tag or digest")
+		}
+	}
+
+	return findings
+}
+
+func containsCapability(list []corev1.Capability, capability corev1.Capability) bool {
+	for _, c := range list {
+		if strings.EqualFold(string(c), string(capability)) {
+			return true
+		}
+	}
+	return false
+}
+
+// imageTag returns the tag of an image reference, or "@digest" for images
+// pinned by digest.
+func imageTag(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		return image[i:]
+	}
+	name := image
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		name = name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+	return ""
+}
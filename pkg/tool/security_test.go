@@ -0,0 +1,101 @@
+package tool
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/utils/ptr"
+)
+
+func TestScanPodSpec(t *testing.T) {
+	hardened := corev1.Container{
+		Name:  "app",
+		Image: "registry.example.com:5000/app:1.2.3",
+		SecurityContext: &corev1.SecurityContext{
+			AllowPrivilegeEscalation: ptr.To(false),
+			ReadOnlyRootFilesystem:   ptr.To(true),
+			RunAsNonRoot:             ptr.To(true),
+			Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+		},
+		Resources: corev1.ResourceRequirements{Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("500m"),
+			corev1.ResourceMemory: resource.MustParse("128Mi"),
+		}},
+	}
+
+	tests := []struct {
+		name   string
+		spec   corev1.PodSpec
+		checks []string
+	}{
+		{
+			name: "hardened pod has no findings",
+			spec: corev1.PodSpec{AutomountServiceAccountToken: ptr.To(false), Containers: []corev1.Container{hardened}},
+		},
+		{
+			name: "host namespaces and host path",
+			spec: corev1.PodSpec{
+				AutomountServiceAccountToken: ptr.To(false),
+				HostNetwork:                  true,
+				HostPID:                      true,
+				Volumes:                      []corev1.Volume{{Name: "root", VolumeSource: corev1.VolumeSource{HostPath: &corev1.HostPathVolumeSource{Path: "/"}}}},
+				Containers:                   []corev1.Container{hardened},
+			},
+			checks: []string{"host-namespaces", "host-namespaces", "host-path"},
+		},
+		{
+			name: "privileged root container with latest image",
+			spec: corev1.PodSpec{
+				AutomountServiceAccountToken: ptr.To(false),
+				Containers: []corev1.Container{func() corev1.Container {
+					c := *hardened.DeepCopy()
+					c.Image = "nginx:latest"
+					c.SecurityContext.Privileged = ptr.To(true)
+					c.SecurityContext.RunAsUser = ptr.To[int64](0)
+					c.SecurityContext.Capabilities.Add = []corev1.Capability{"SYS_ADMIN"}
+					return c
+				}()},
+			},
+			checks: []string{"privileged", "capabilities", "run-as-root", "image-tag"},
+		},
+		{
+			name: "defaults",
+			spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "nginx"}}},
+			checks: []string{
+				"service-account-token", "capabilities", "privilege-escalation", "run-as-root",
+				"read-only-root-filesystem", "resource-limits", "image-tag",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			findings := scanPodSpec("Pod default/test", &tt.spec)
+			if len(findings) != len(tt.checks) {
+				t.Fatalf("scanPodSpec() got %d findings %+v, want %v", len(findings), findings, tt.checks)
+			}
+			for i, f := range findings {
+				if f.Check != tt.checks[i] {
+					t.Errorf("finding %d check = %v, want %v", i, f.Check, tt.checks[i])
+				}
+			}
+		})
+	}
+}
+
+func TestImageTag(t *testing.T) {
+	tests := map[string]string{
+		"nginx":                                 "",
+		"nginx:1.27":                            "1.27",
+		"registry:5000/team/app":                "",
+		"registry:5000/team/app:v2":             "v2",
+		"nginx@sha256:abc":                      "@sha256:abc",
+		"ghcr.io/org/app:latest":                "latest",
+		"registry.example.com:5000/app@sha256:": "@sha256:",
+	}
+	for image, want := range tests {
+		if got := imageTag(image); got != want {
+			t.Errorf("imageTag(%q) = %q, want %q", image, got, want)
+		}
+	}
+}
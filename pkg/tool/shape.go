@@ -0,0 +1,364 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"unicode/utf8"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+)
+
+// defaultMaxOutputBytes caps the size of a tool's text output, roughly 16k
+// tokens, to protect the model's context window.
+const defaultMaxOutputBytes = 64 * 1024
+
+// shapeOptions controls how object output is shaped.
+type shapeOptions struct {
+	// fields are JSONPaths to keep of each object; all fields are kept when empty.
+	fields []string
+	// maxBytes is the size above which lists fall back to a summary table and
+	// other output is truncated. Zero disables the limit.
+	maxBytes int
+}
+
+// shapeObjects shapes the JSON or YAML encoding of an object or list of
+// objects: managedFields and the last-applied annotation are stripped, fields
+// are projected and oversized lists are summarised as a table. JSON is
+// re-encoded compactly. Output that doesn't decode to an object is returned
+// unchanged.
+func shapeObjects(data, format string, opts shapeOptions) (string, error) {
+	raw := []byte(data)
+	if format == "yaml" {
+		converted, err := yaml.YAMLToJSON(raw)
+		if err != nil {
+			return data, nil
+		}
+		raw = converted
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return data, nil
+	}
+
+	items, isList := obj["items"].([]interface{})
+	if !isList {
+		items = []interface{}{obj}
+	}
+	for _, item := range items {
+		if o, ok := item.(map[string]interface{}); ok {
+			stripNoise(o)
+		}
+	}
+	// Keep the full objects for the summary table in case fields are projected.
+	objects := items
+	if len(opts.fields) > 0 {
+		projected := make([]interface{}, len(items))
+		for i, item := range items {
+			o, _ := item.(map[string]interface{})
+			p, err := projectFields(o, opts.fields)
+			if err != nil {
+				return "", err
+			}
+			projected[i] = p
+		}
+		items = projected
+	}
+
+	var shaped interface{} = items[0]
+	if isList {
+		if len(opts.fields) > 0 {
+			shaped = map[string]interface{}{"items": items}
+		} else {
+			obj["items"] = items
+			shaped = obj
+		}
+	}
+
+	var out []byte
+	var err error
+	if format == "yaml" {
+		out, err = yaml.Marshal(shaped)
+	} else {
+		out, err = json.Marshal(shaped)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to encode output: %w", err)
+	}
+
+	if opts.maxBytes > 0 && len(out) > opts.maxBytes && isList {
+		return summaryTable(objects, opts.maxBytes), nil
+	}
+	return string(out), nil
+}
+
+// stripNoise removes the fields of an object that are rarely useful to read
+// but can make up most of its size.
+func stripNoise(obj map[string]interface{}) {
+	metadata, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		return
+	}
+	delete(metadata, "managedFields")
+	if annotations, ok := metadata["annotations"].(map[string]interface{}); ok {
+		delete(annotations, lastAppliedAnnotation)
+		if len(annotations) == 0 {
+			delete(metadata, "annotations")
+		}
+	}
+}
+
+// projectFields returns the values of the JSONPaths in the object, keyed by
+// path. Paths may be given with or without braces and the leading dot, e.g.
+// "metadata.name", ".status.phase" or "{.spec.containers[*].image}". Paths
+// matching several values map to a list.
+func projectFields(obj map[string]interface{}, fields []string) (map[string]interface{}, error) {
+	projected := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		path := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(field), "{"), "}")
+		if !strings.HasPrefix(path, ".") {
+			path = "." + path
+		}
+
+		jp := jsonpath.New(field).AllowMissingKeys(true)
+		if err := jp.Parse("{" + path + "}"); err != nil {
+			return nil, fmt.Errorf("invalid field %q: %w", field, err)
+		}
+		results, err := jp.FindResults(obj)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate field %q: %w", field, err)
+		}
+
+		var values []interface{}
+		for _, result := range results {
+			for _, v := range result {
+				values = append(values, v.Interface())
+			}
+		}
+		switch len(values) {
+		case 0:
+			projected[field] = nil
+		case 1:
+			projected[field] = values[0]
+		default:
+			projected[field] = values
+		}
+	}
+	return projected, nil
+}
+
+// summaryTable renders a table with one line per object, with as many rows as
+// fit in maxBytes, followed by a note on how many items were left out.
+func summaryTable(items []interface{}, maxBytes int) string {
+	var sb strings.Builder
+	w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "KIND\tNAMESPACE\tNAME\tSTATUS\tCREATED")
+	for _, item := range items {
+		o, _ := item.(map[string]interface{})
+		metadata, _ := o["metadata"].(map[string]interface{})
+		status, _ := o["status"].(map[string]interface{})
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
+			cell(o["kind"]), cell(metadata["namespace"]), cell(metadata["name"]),
+			cell(status["phase"]), cell(metadata["creationTimestamp"]))
+	}
+	_ = w.Flush()
+
+	header := fmt.Sprintf("Output of %d items exceeds %d bytes, showing a summary; pass fields or narrow the query for details\n\n", len(items), maxBytes)
+	lines := strings.SplitAfter(sb.String(), "\n")
+	var out strings.Builder
+	out.WriteString(header)
+	shown := 0
+	for _, line := range lines[:len(lines)-1] {
+		if out.Len()+len(line) > maxBytes && shown > 0 {
+			break
+		}
+		out.WriteString(line)
+		shown++
+	}
+	// The first line is the table header.
+	if more := len(items) - (shown - 1); more > 0 {
+		out.WriteString(fmt.Sprintf("... truncated, %d more items", more))
+	}
+	return out.String()
+}
+
+func cell(v interface{}) string {
+	if v == nil || v == "" {
+		return "-"
+	}
+	return fmt.Sprint(v)
+}
+
+// truncateText cuts text to at most maxBytes on a rune boundary and notes how
+// much was left out.
+func truncateText(text string, maxBytes int) string {
+	if maxBytes <= 0 || len(text) <= maxBytes {
+		return text
+	}
+	cut := maxBytes
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + fmt.Sprintf("\n... truncated, %d more bytes", len(text)-cut)
+}
+
+// limitOutput wraps a tool handler so that no text content, and no stdout or
+// stderr of a kubectl result, exceeds the handler's max output size, and
+// structured content is cut down to about that size with limitStructured.
+func (h *Handler) limitOutput(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	if h.maxOutputBytes <= 0 {
+		return handler
+	}
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, req)
+		if result == nil {
+			return result, err
+		}
+		for i, content := range result.Content {
+			if text, ok := content.(mcp.TextContent); ok && len(text.Text) > h.maxOutputBytes {
+				text.Text = truncateText(text.Text, h.maxOutputBytes)
+				result.Content[i] = text
+			}
+		}
+		switch structured := result.StructuredContent.(type) {
+		case nil:
+			return result, err
+		case KubectlResult:
+			result.StructuredContent = h.limitKubectlResult(structured)
+		case KubectlGetResult:
+			structured.KubectlResult = h.limitKubectlResult(structured.KubectlResult)
+			size := 0
+			for i, item := range structured.Items {
+				data, _ := json.Marshal(item)
+				if size += len(data); size > h.maxOutputBytes {
+					structured.MoreItems = len(structured.Items) - i
+					structured.Items = structured.Items[:i]
+					break
+				}
+			}
+			result.StructuredContent = structured
+		}
+		result.StructuredContent = limitStructured(result.StructuredContent, h.maxOutputBytes)
+		return result, err
+	}
+}
+
+// truncatedField is the field limitStructured adds to structured content it
+// cut down, mapping the path of each shortened list to the number of items
+// left out.
+const truncatedField = "truncated"
+
+// limitStructured returns v unchanged when its JSON encoding fits in maxBytes.
+// Otherwise it returns the decoded JSON with the largest lists halved, or the
+// longest strings truncated, until it fits, keeping the shape the output
+// schema describes. The paths of shortened lists and the number of items left
+// out are added to an object as truncatedField.
+func limitStructured(v any, maxBytes int) any {
+	data, err := json.Marshal(v)
+	if err != nil || len(data) <= maxBytes {
+		return v
+	}
+	var generic any
+	if err := json.Unmarshal(data, &generic); err != nil {
+		return v
+	}
+
+	truncated := map[string]int{}
+	for size := len(data); size > maxBytes; {
+		largest := largestValue(generic, "", nil)
+		if largest == nil {
+			break
+		}
+		switch value := largest.value.(type) {
+		case []any:
+			keep := len(value) / 2
+			truncated[largest.path] += len(value) - keep
+			largest.set(value[:keep])
+		case string:
+			largest.set(truncateText(value, len(value)/2))
+		}
+		data, _ = json.Marshal(generic)
+		size = len(data)
+	}
+
+	if obj, ok := generic.(map[string]any); ok && len(truncated) > 0 {
+		obj[truncatedField] = truncated
+	}
+	return generic
+}
+
+// sizedValue is a list or string in decoded JSON with the function that
+// replaces it.
+type sizedValue struct {
+	path  string
+	value any
+	size  int
+	set   func(any)
+}
+
+// minTruncatedString is the length under which strings are left alone, so
+// names and other short values are never cut.
+const minTruncatedString = 256
+
+// largestValue returns the list of several items or long string in v with the
+// largest JSON encoding, or nil when there is none.
+func largestValue(v any, path string, set func(any)) *sizedValue {
+	var largest *sizedValue
+	consider := func(candidate *sizedValue) {
+		if candidate != nil && (largest == nil || candidate.size > largest.size) {
+			largest = candidate
+		}
+	}
+
+	switch value := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(value))
+		for k := range value {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			childPath := k
+			if path != "" {
+				childPath = path + "." + k
+			}
+			consider(largestValue(value[k], childPath, func(child any) { value[k] = child }))
+		}
+	case []any:
+		// A single item is left for its own lists and strings to be cut.
+		if len(value) > 1 && set != nil {
+			data, _ := json.Marshal(value)
+			consider(&sizedValue{path: path, value: value, size: len(data), set: set})
+		}
+		for i := range value {
+			consider(largestValue(value[i], fmt.Sprintf("%s[%d]", path, i), func(child any) { value[i] = child }))
+		}
+	case string:
+		if len(value) >= minTruncatedString && set != nil {
+			consider(&sizedValue{path: path, value: value, size: len(value), set: set})
+		}
+	}
+	return largest
+}
+
+func (h *Handler) limitKubectlResult(result KubectlResult) KubectlResult {
+	result.Stdout = truncateText(result.Stdout, h.maxOutputBytes)
+	result.Stderr = truncateText(result.Stderr, h.maxOutputBytes)
+	return result
+}
+
+// shapeKubectlOutput shapes kubectl output in the json or yaml format and
+// returns other formats unchanged.
+func (h *Handler) shapeKubectlOutput(stdout, format string, fields []string) (string, error) {
+	if stdout == "" || (format != "json" && format != "yaml") {
+		return stdout, nil
+	}
+	return shapeObjects(stdout, format, shapeOptions{fields: fields, maxBytes: h.maxOutputBytes})
+}
@@ -0,0 +1,143 @@
+package tool
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const shapeTestList = `{"apiVersion":"v1","kind":"List","items":[
+	{"apiVersion":"v1","kind":"Pod","metadata":{"name":"web","namespace":"default",
+		"annotations":{"kubectl.kubernetes.io/last-applied-configuration":"{}"},
+		"managedFields":[{"manager":"kubectl"}]},
+	 "spec":{"containers":[{"name":"app","image":"nginx"},{"name":"sidecar","image":"envoy"}]},
+	 "status":{"phase":"Running"}}]}`
+
+func TestShapeObjectsStripsNoise(t *testing.T) {
+	got, err := shapeObjects(shapeTestList, "json", shapeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "managedFields") || strings.Contains(got, "last-applied") || strings.Contains(got, "annotations") {
+		t.Errorf("shapeObjects() kept noise: %s", got)
+	}
+	if !strings.Contains(got, `"phase":"Running"`) {
+		t.Errorf("shapeObjects() dropped fields: %s", got)
+	}
+}
+
+func TestShapeObjectsFields(t *testing.T) {
+	got, err := shapeObjects(shapeTestList, "json", shapeOptions{
+		fields: []string{"metadata.name", "{.status.phase}", ".spec.containers[*].image", ".missing"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"items":[{".missing":null,".spec.containers[*].image":["nginx","envoy"],"metadata.name":"web","{.status.phase}":"Running"}]}`
+	if got != want {
+		t.Errorf("shapeObjects() = %s, want %s", got, want)
+	}
+
+	if _, err := shapeObjects(shapeTestList, "json", shapeOptions{fields: []string{".items[?"}}); err == nil {
+		t.Error("shapeObjects() accepted an invalid JSONPath")
+	}
+}
+
+func TestShapeObjectsYAML(t *testing.T) {
+	input := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: settings\n  managedFields:\n  - manager: kubectl\ndata:\n  key: value\n"
+	got, err := shapeObjects(input, "yaml", shapeOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(got, "managedFields") || !strings.Contains(got, "key: value") {
+		t.Errorf("shapeObjects() = %s", got)
+	}
+}
+
+func TestShapeObjectsSummaryTable(t *testing.T) {
+	var items []string
+	for i := 0; i < 100; i++ {
+		items = append(items, fmt.Sprintf(`{"kind":"Pod","metadata":{"name":"pod-%d","namespace":"default"},"status":{"phase":"Running"},"spec":{"nodeName":"%s"}}`, i, strings.Repeat("x", 100)))
+	}
+	input := `{"kind":"List","items":[` + strings.Join(items, ",") + `]}`
+
+	got, err := shapeObjects(input, "json", shapeOptions{maxBytes: 2048})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if json.Valid([]byte(got)) {
+		t.Fatalf("shapeObjects() returned JSON for an oversized list: %.100s", got)
+	}
+	if !strings.Contains(got, "KIND") || !strings.Contains(got, "pod-0") {
+		t.Errorf("summary table is missing rows: %s", got)
+	}
+	if !strings.Contains(got, "more items") {
+		t.Errorf("summary table is missing the truncation note: %s", got)
+	}
+	if len(got) > 2048+64 {
+		t.Errorf("summary table is %d bytes, want about 2048", len(got))
+	}
+}
+
+func TestShapeObjectsPassesThroughNonObjects(t *testing.T) {
+	input := "NAME   READY\nweb    1/1\n"
+	got, err := shapeObjects(input, "json", shapeOptions{})
+	if err != nil || got != input {
+		t.Errorf("shapeObjects() = %q, %v, want the input unchanged", got, err)
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		maxBytes int
+		want     string
+	}{
+		{name: "short", text: "hello", maxBytes: 10, want: "hello"},
+		{name: "no limit", text: "hello", maxBytes: 0, want: "hello"},
+		{name: "cut", text: "hello world", maxBytes: 5, want: "hello\n... truncated, 6 more bytes"},
+		{name: "rune boundary", text: "héllo", maxBytes: 2, want: "h\n... truncated, 5 more bytes"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateText(tt.text, tt.maxBytes); got != tt.want {
+				t.Errorf("truncateText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitStructured(t *testing.T) {
+	findings := make([]securityFinding, 200)
+	for i := range findings {
+		findings[i] = securityFinding{Check: "privileged", Object: fmt.Sprintf("default/Deployment/web-%d", i)}
+	}
+	scan := securityScanResult{Scope: "cluster", TotalFindings: len(findings), Findings: findings}
+
+	if got := limitStructured(scan, 1<<20); !reflect.DeepEqual(got, scan) {
+		t.Error("limitStructured() changed a value that fits")
+	}
+
+	got, _ := limitStructured(scan, 4096).(map[string]any)
+	data, _ := json.Marshal(got)
+	if len(data) > 4096 {
+		t.Errorf("limited output is %d bytes, want at most 4096", len(data))
+	}
+	kept, _ := got["findings"].([]any)
+	truncated, _ := got[truncatedField].(map[string]int)
+	if len(kept) == 0 || len(kept)+truncated["findings"] != len(findings) {
+		t.Errorf("kept %d findings and reported %v left out, want %d in total", len(kept), truncated, len(findings))
+	}
+	if got["totalFindings"] != float64(len(findings)) {
+		t.Errorf("totalFindings = %v, want the count before truncation", got["totalFindings"])
+	}
+
+	query := KubectlGetResult{QueryResult: strings.Repeat("x", 10000)}
+	got, _ = limitStructured(query, 2048).(map[string]any)
+	if text, _ := got["queryResult"].(string); len(text) > 2048 || !strings.Contains(text, "truncated") {
+		t.Errorf("queryResult was not truncated: %d bytes", len(text))
+	}
+}
@@ -0,0 +1,115 @@
+package tool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/idebeijer/kube-mcp-server/pkg/resource"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+type summarizeResourcesResult struct {
+	Kind        string                   `json:"kind"`
+	Namespace   string                   `json:"namespace,omitempty"`
+	TotalItems  int                      `json:"totalItems"`
+	Items       []map[string]interface{} `json:"items,omitempty"`
+	QueryResult interface{}              `json:"queryResult,omitempty"`
+}
+
+func (h *Handler) registerSummaries(m *server.MCPServer) {
+	h.addTool(m, mcp.NewTool("summarize_resources",
+		mcp.WithDescription("List objects of a kind with the same summary fields as the k8s:// resources, as a table or JSON"),
+		mcp.WithOutputSchema[summarizeResourcesResult](),
+		mcp.WithString("kind",
+			mcp.Description("Kind to summarise, one of: "+strings.Join(h.summaries.Names(), ", ")),
+			mcp.Required(),
+			mcp.Enum(h.summaries.Names()...),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace to list in; empty lists all namespaces. Ignored for cluster-scoped kinds"),
+		),
+		mcp.WithString("output",
+			mcp.Description("Output format: table or json"),
+			mcp.Enum("table", "json"),
+			mcp.DefaultString("table"),
+		),
+		mcp.WithString("query",
+			mcp.Description("jq expression evaluated over the list of summaries, returning only its result, e.g. '[.[] | select(.status != \"Running\") | .name]' or 'group_by(.node) | map({node: .[0].node, count: length})'"),
+		),
+	), mcp.NewTypedToolHandler[SummarizeResourcesArgs](h.summarizeResourcesHandler()))
+}
+
+type SummarizeResourcesArgs struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Output    string `json:"output,omitempty"`
+	Query     string `json:"query,omitempty"`
+}
+
+func (h *Handler) summarizeResourcesHandler() mcp.TypedToolHandlerFunc[SummarizeResourcesArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args SummarizeResourcesArgs,
+	) (*mcp.CallToolResult, error) {
+		kind, ok := h.summaries.Lookup(args.Kind)
+		if !ok {
+			return mcp.NewToolResultError(fmt.Sprintf("unknown kind %q, expected one of: %s", args.Kind, strings.Join(h.summaries.Names(), ", "))), nil
+		}
+
+		summaries, err := kind.List(ctx, h.dynamic, args.Namespace)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to summarise resources", err), nil
+		}
+
+		structured := summarizeResourcesResult{
+			Kind:       kind.Name,
+			TotalItems: len(summaries),
+			Items:      summaries,
+		}
+		if kind.Namespaced {
+			structured.Namespace = args.Namespace
+		}
+
+		if args.Query != "" {
+			data, err := json.Marshal(summaries)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to marshal summaries", err), nil
+			}
+			queryResult, text, err := queryJSON(ctx, args.Query, data)
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to run query", err), nil
+			}
+			structured.Items = nil
+			structured.QueryResult = queryResult
+			return mcp.NewToolResultStructured(structured, text), nil
+		}
+
+		switch args.Output {
+		case "", "table":
+			if len(summaries) == 0 {
+				return mcp.NewToolResultStructured(structured, fmt.Sprintf("No %s found", kind.Name)), nil
+			}
+			return mcp.NewToolResultStructured(structured, kind.Table(summaries)), nil
+		case "json":
+			result, err := json.MarshalIndent(summaries, "", "  ")
+			if err != nil {
+				return mcp.NewToolResultErrorFromErr("failed to marshal summaries", err), nil
+			}
+			return mcp.NewToolResultStructured(structured, string(result)), nil
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf("unknown output format %q, expected table or json", args.Output)), nil
+		}
+	}
+}
+
+// WithSummaries sets the registry of kinds the summarize_resources tool can
+// list. It defaults to resource.NewDefaultRegistry.
+func WithSummaries(registry *resource.Registry) Option {
+	return func(h *Handler) {
+		h.summaries = registry
+	}
+}
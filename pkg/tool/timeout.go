@@ -0,0 +1,220 @@
+package tool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// defaultToolTimeout bounds how long a tool call may run.
+	defaultToolTimeout = 2 * time.Minute
+	// defaultMaxToolTimeout is the longest timeout a call can ask for.
+	defaultMaxToolTimeout = 10 * time.Minute
+	// kubectlKillDelay is how long a cancelled kubectl process gets to exit
+	// after SIGTERM before it is killed.
+	kubectlKillDelay = 5 * time.Second
+	timeoutArg       = "timeout"
+
+	cancelledNotification = "notifications/cancelled"
+	// requestIDMetaKey is the _meta field the request ID of a tool call is
+	// kept in, so the call can be matched to a cancellation notification.
+	requestIDMetaKey = "kube-mcp-server/requestId"
+)
+
+// WithToolTimeout sets how long a tool call may run unless the tool has its
+// own timeout or the call asks for one.
+func WithToolTimeout(timeout time.Duration) Option {
+	return func(h *Handler) {
+		if timeout > 0 {
+			h.toolTimeout = timeout
+		}
+	}
+}
+
+// WithToolTimeoutOverrides sets the timeout of individual tools by name.
+func WithToolTimeoutOverrides(timeouts map[string]time.Duration) Option {
+	return func(h *Handler) {
+		h.toolTimeouts = timeouts
+	}
+}
+
+// WithMaxToolTimeout sets the longest timeout a call can ask for with the
+// timeout argument.
+func WithMaxToolTimeout(timeout time.Duration) Option {
+	return func(h *Handler) {
+		if timeout > 0 {
+			h.maxToolTimeout = timeout
+		}
+	}
+}
+
+// defaultTimeout returns the timeout configured for the tool.
+func (h *Handler) defaultTimeout(toolName string) time.Duration {
+	if override, ok := h.toolTimeouts[toolName]; ok && override > 0 {
+		return override
+	}
+	return h.toolTimeout
+}
+
+// timeoutFor returns the timeout of a tool call: the timeout argument, capped
+// at the max tool timeout, or else the timeout configured for the tool.
+func (h *Handler) timeoutFor(toolName string, args map[string]interface{}) (time.Duration, error) {
+	timeout := h.defaultTimeout(toolName)
+	switch v := args[timeoutArg].(type) {
+	case nil:
+		return timeout, nil
+	case string:
+		if v == "" {
+			return timeout, nil
+		}
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			seconds, numErr := strconv.ParseFloat(v, 64)
+			if numErr != nil {
+				return 0, fmt.Errorf("invalid timeout %q: expected a duration like 30s or 2m", v)
+			}
+			d = time.Duration(seconds * float64(time.Second))
+		}
+		timeout = d
+	case float64:
+		timeout = time.Duration(v * float64(time.Second))
+	default:
+		return 0, fmt.Errorf("invalid timeout %v: expected a duration like 30s or 2m", v)
+	}
+
+	if timeout <= 0 {
+		return 0, fmt.Errorf("invalid timeout %s: must be positive", timeout)
+	}
+	if timeout > h.maxToolTimeout {
+		timeout = h.maxToolTimeout
+	}
+	return timeout, nil
+}
+
+// limitTime wraps a tool handler so the call is cancelled, along with any
+// kubectl process it started, once its timeout passes.
+func (h *Handler) limitTime(toolName string, handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		timeout, err := h.timeoutFor(toolName, req.GetArguments())
+		if err != nil {
+			return mcp.NewToolResultError(err.Error()), nil
+		}
+
+		ctx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		result, err := handler(ctx, withoutArgument(req, timeoutArg))
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			log.Warn().Str("tool", toolName).Dur("timeout", timeout).Msg("Tool call timed out")
+			return mcp.NewToolResultError(fmt.Sprintf("%s timed out after %s; narrow the request or pass a longer %s (at most %s)",
+				toolName, timeout, timeoutArg, h.maxToolTimeout)), nil
+		}
+		return result, err
+	}
+}
+
+// AddHooks registers the hooks the handler relies on, which record the
+// request ID of each tool call so cancellation notifications can be matched
+// to it.
+func (h *Handler) AddHooks(hooks *server.Hooks) {
+	hooks.AddBeforeCallTool(func(ctx context.Context, id any, message *mcp.CallToolRequest) {
+		if message.Params.Meta == nil {
+			message.Params.Meta = &mcp.Meta{}
+		}
+		if message.Params.Meta.AdditionalFields == nil {
+			message.Params.Meta.AdditionalFields = map[string]any{}
+		}
+		// The ID is passed as decoded from JSON; normalise it like the ID in a
+		// cancellation notification.
+		requestID, ok := id.(mcp.RequestId)
+		if !ok {
+			requestID = mcp.NewRequestId(id)
+		}
+		message.Params.Meta.AdditionalFields[requestIDMetaKey] = requestID.String()
+	})
+}
+
+// inflightCalls keeps the cancel functions of the running tool calls, keyed
+// by session and request ID.
+type inflightCalls struct {
+	mu    sync.Mutex
+	calls map[string]context.CancelFunc
+}
+
+func newInflightCalls() *inflightCalls {
+	return &inflightCalls{calls: map[string]context.CancelFunc{}}
+}
+
+func (c *inflightCalls) add(key string, cancel context.CancelFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls[key] = cancel
+}
+
+func (c *inflightCalls) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.calls, key)
+}
+
+// cancel cancels the call and reports whether it was running.
+func (c *inflightCalls) cancel(key string) bool {
+	c.mu.Lock()
+	cancel, ok := c.calls[key]
+	c.mu.Unlock()
+	if ok {
+		cancel()
+	}
+	return ok
+}
+
+func callKey(ctx context.Context, requestID string) string {
+	session := ""
+	if s := server.ClientSessionFromContext(ctx); s != nil {
+		session = s.SessionID()
+	}
+	return session + "/" + requestID
+}
+
+// cancellable wraps a tool handler so a cancellation notification for the
+// call cancels its context.
+func (h *Handler) cancellable(handler server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var requestID string
+		if meta := req.Params.Meta; meta != nil {
+			requestID, _ = meta.AdditionalFields[requestIDMetaKey].(string)
+		}
+		if requestID == "" {
+			return handler(ctx, req)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		key := callKey(ctx, requestID)
+		h.inflight.add(key, cancel)
+		defer h.inflight.remove(key)
+
+		result, err := handler(ctx, req)
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return mcp.NewToolResultError(fmt.Sprintf("%s was cancelled by the client", req.Params.Name)), nil
+		}
+		return result, err
+	}
+}
+
+// handleCancelled cancels the tool call named by a cancellation notification.
+func (h *Handler) handleCancelled(ctx context.Context, notification mcp.JSONRPCNotification) {
+	requestID := mcp.NewRequestId(notification.Params.AdditionalFields["requestId"]).String()
+	if h.inflight.cancel(callKey(ctx, requestID)) {
+		reason, _ := notification.Params.AdditionalFields["reason"].(string)
+		log.Info().Str("request", requestID).Str("reason", reason).Msg("Tool call cancelled by the client")
+	}
+}
@@ -0,0 +1,141 @@
+package tool
+
+import (
+	"context"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+func TestTimeoutFor(t *testing.T) {
+	h := &Handler{
+		toolTimeout:    time.Minute,
+		toolTimeouts:   map[string]time.Duration{"kubectl_logs": 5 * time.Minute},
+		maxToolTimeout: 10 * time.Minute,
+	}
+	tests := []struct {
+		name    string
+		tool    string
+		timeout interface{}
+		want    time.Duration
+		wantErr bool
+	}{
+		{name: "default", tool: "kubectl_get", want: time.Minute},
+		{name: "per tool", tool: "kubectl_logs", want: 5 * time.Minute},
+		{name: "duration argument", tool: "kubectl_get", timeout: "30s", want: 30 * time.Second},
+		{name: "seconds as string", tool: "kubectl_get", timeout: "90", want: 90 * time.Second},
+		{name: "seconds as number", tool: "kubectl_get", timeout: float64(2.5), want: 2500 * time.Millisecond},
+		{name: "capped", tool: "kubectl_logs", timeout: "1h", want: 10 * time.Minute},
+		{name: "empty", tool: "kubectl_get", timeout: "", want: time.Minute},
+		{name: "invalid", tool: "kubectl_get", timeout: "soon", wantErr: true},
+		{name: "negative", tool: "kubectl_get", timeout: "-5s", wantErr: true},
+		{name: "wrong type", tool: "kubectl_get", timeout: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]interface{}{}
+			if tt.timeout != nil {
+				args["timeout"] = tt.timeout
+			}
+			got, err := h.timeoutFor(tt.tool, args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("timeoutFor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("timeoutFor() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitTime(t *testing.T) {
+	h := &Handler{toolTimeout: time.Minute, maxToolTimeout: time.Minute}
+	handler := h.limitTime("kubectl_logs", func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if _, ok := req.GetArguments()["timeout"]; ok {
+			t.Error("timeout argument was passed on to the handler")
+		}
+		<-ctx.Done()
+		return mcp.NewToolResultText("partial output"), nil
+	})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Arguments = map[string]interface{}{"timeout": "50ms"}
+	result, err := handler(context.Background(), req)
+	if err != nil {
+		t.Fatalf("handler returned error: %v", err)
+	}
+	if !result.IsError || !strings.Contains(resultText(result), "timed out after 50ms") {
+		t.Errorf("result = %q, want a timeout error", resultText(result))
+	}
+}
+
+func TestCancellable(t *testing.T) {
+	h := &Handler{inflight: newInflightCalls()}
+	started := make(chan struct{})
+	handler := h.cancellable(func(ctx context.Context, req mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		<-ctx.Done()
+		return mcp.NewToolResultText("partial output"), nil
+	})
+
+	req := mcp.CallToolRequest{}
+	req.Params.Name = "kubectl_logs"
+	hooks := &server.Hooks{}
+	h.AddHooks(hooks)
+	for _, hook := range hooks.OnBeforeCallTool {
+		hook(context.Background(), float64(7), &req)
+	}
+	done := make(chan *mcp.CallToolResult)
+	go func() {
+		result, _ := handler(context.Background(), req)
+		done <- result
+	}()
+	<-started
+
+	notification := mcp.JSONRPCNotification{}
+	notification.Params.AdditionalFields = map[string]any{"requestId": float64(8)}
+	h.handleCancelled(context.Background(), notification)
+	select {
+	case <-done:
+		t.Fatal("cancelling another request cancelled the call")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	notification.Params.AdditionalFields["requestId"] = float64(7)
+	h.handleCancelled(context.Background(), notification)
+	select {
+	case result := <-done:
+		if !result.IsError || !strings.Contains(resultText(result), "cancelled") {
+			t.Errorf("result = %q, want a cancellation error", resultText(result))
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("call was not cancelled")
+	}
+	if len(h.inflight.calls) != 0 {
+		t.Errorf("%d calls still tracked after they finished", len(h.inflight.calls))
+	}
+}
+
+func TestRunKubectlCancel(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a POSIX shell")
+	}
+	// The shell stands in for kubectl; the background sleep for a child
+	// process, like a credential plugin, that must not outlive the call.
+	h := &Handler{kubectlPath: "/bin/sh"}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := h.runKubectl(ctx, "-c", "sleep 30 & wait")
+	if err == nil {
+		t.Fatal("runKubectl() succeeded, want an error for the cancelled command")
+	}
+	if elapsed := time.Since(start); elapsed > kubectlKillDelay {
+		t.Errorf("runKubectl() returned after %s, want the process group terminated right away", elapsed)
+	}
+}
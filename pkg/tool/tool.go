@@ -3,14 +3,57 @@ package tool
 import (
 	"fmt"
 	"os/exec"
+	"time"
 
+	"github.com/idebeijer/kube-mcp-server/pkg/kube"
+	"github.com/idebeijer/kube-mcp-server/pkg/policy"
+	"github.com/idebeijer/kube-mcp-server/pkg/resource"
+	"github.com/idebeijer/kube-mcp-server/pkg/toolset"
 	"github.com/mark3labs/mcp-go/server"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/restmapper"
 )
 
+const defaultFieldManager = "kube-mcp-server"
+
 type Handler struct {
 	client         *kubernetes.Clientset
+	dynamic        dynamic.Interface
+	metadata       metadata.Interface
+	discovery      discovery.CachedDiscoveryInterface
+	mapper         *restmapper.DeferredDiscoveryRESTMapper
 	kubeconfigPath string
+	fieldManager   string
+
+	defaultNamespace string
+	permissionCheck  PermissionCheckMode
+	rules            *authorizationv1.SelfSubjectRulesReview
+	namespaceRules   *namespaceRules
+	ownerIndexes     *ownerIndexCache
+	summaries        *resource.Registry
+	toolset          *toolset.Filter
+	maxOutputBytes   int
+
+	policy      *policy.Engine
+	caller      policy.Caller
+	kubeContext string
+
+	destructiveConfirmation bool
+	confirmations           *confirmStore
+
+	rateLimiter          *rateLimiter
+	maxConcurrentKubectl int
+	kubectlSlots         chan struct{}
+
+	toolTimeout    time.Duration
+	toolTimeouts   map[string]time.Duration
+	maxToolTimeout time.Duration
+	inflight       *inflightCalls
 
 	kubectlEnabled bool
 	kubectlPath    string
@@ -24,14 +67,90 @@ func WithKubectlTools() Option {
 	}
 }
 
-func NewHandler(client *kubernetes.Clientset, kubeconfigPath string, opts ...Option) (*Handler, error) {
+// WithFieldManager sets the default field manager used for server-side apply.
+func WithFieldManager(name string) Option {
+	return func(h *Handler) {
+		if name != "" {
+			h.fieldManager = name
+		}
+	}
+}
+
+// WithDefaultNamespace sets the namespace used when a tool call doesn't specify one.
+func WithDefaultNamespace(namespace string) Option {
+	return func(h *Handler) {
+		if namespace != "" {
+			h.defaultNamespace = namespace
+		}
+	}
+}
+
+// WithPermissionCheck sets whether tools the current identity can't use are
+// annotated, hidden or registered as-is.
+func WithPermissionCheck(mode PermissionCheckMode) Option {
+	return func(h *Handler) {
+		if mode != "" {
+			h.permissionCheck = mode
+		}
+	}
+}
+
+// WithMaxOutputBytes sets the size above which tool output is summarised or
+// truncated. Zero disables the limit.
+func WithMaxOutputBytes(bytes int) Option {
+	return func(h *Handler) {
+		if bytes >= 0 {
+			h.maxOutputBytes = bytes
+		}
+	}
+}
+
+// WithToolset registers only the tools the filter allows.
+func WithToolset(filter *toolset.Filter) Option {
+	return func(h *Handler) {
+		h.toolset = filter
+	}
+}
+
+func NewHandler(client *kubernetes.Clientset, dynamicClient dynamic.Interface, metadataClient metadata.Interface, kubeconfigPath string, opts ...Option) (*Handler, error) {
+	cachedDiscovery := memory.NewMemCacheClient(client.Discovery())
 	h := &Handler{
 		client:         client,
+		dynamic:        dynamicClient,
+		metadata:       metadataClient,
+		discovery:      cachedDiscovery,
+		mapper:         restmapper.NewDeferredDiscoveryRESTMapper(cachedDiscovery),
 		kubeconfigPath: kubeconfigPath,
+		fieldManager:   defaultFieldManager,
+
+		defaultNamespace: "default",
+		permissionCheck:  PermissionCheckAnnotate,
+		namespaceRules:   newNamespaceRules(),
+		ownerIndexes:     newOwnerIndexCache(ownerIndexTTL),
+		maxOutputBytes:   defaultMaxOutputBytes,
+		kubeContext:      kube.CurrentContext(kubeconfigPath),
+
+		destructiveConfirmation: true,
+		confirmations:           newConfirmStore(defaultConfirmTokenTTL),
+
+		maxConcurrentKubectl: defaultMaxConcurrentKubectl,
+
+		toolTimeout:    defaultToolTimeout,
+		maxToolTimeout: defaultMaxToolTimeout,
+		inflight:       newInflightCalls(),
 	}
 	for _, opt := range opts {
 		opt(h)
 	}
+	if h.summaries == nil {
+		h.summaries = resource.NewDefaultRegistry()
+	}
+
+	switch h.permissionCheck {
+	case PermissionCheckOff, PermissionCheckAnnotate, PermissionCheckHide:
+	default:
+		return nil, fmt.Errorf("unknown permission check mode: %s", h.permissionCheck)
+	}
 
 	if h.kubectlEnabled {
 		path, err := exec.LookPath("kubectl")
@@ -39,13 +158,38 @@ func NewHandler(client *kubernetes.Clientset, kubeconfigPath string, opts ...Opt
 			return nil, fmt.Errorf("kubectl not installed or not found in PATH: %w", err)
 		}
 		h.kubectlPath = path
+		if h.maxConcurrentKubectl > 0 {
+			h.kubectlSlots = make(chan struct{}, h.maxConcurrentKubectl)
+		}
 	}
 
 	return h, nil
 }
 
 func (h *Handler) Register(m *server.MCPServer) {
+	if h.permissionCheck != PermissionCheckOff {
+		h.loadPermissions()
+	}
+	if h.policy != nil {
+		h.loadCaller()
+	}
+	m.AddNotificationHandler(cancelledNotification, h.handleCancelled)
+
 	h.registerPods(m)
+	h.registerApply(m)
+	h.registerDiff(m)
+	h.registerPatch(m)
+	h.registerDiscovery(m)
+	h.registerAuth(m)
+	h.registerRBAC(m)
+	h.registerSecurity(m)
+	h.registerDeprecated(m)
+	h.registerHealth(m)
+	h.registerTrace(m)
+	h.registerOwner(m)
+	h.registerHelm(m)
+	h.registerJobs(m)
+	h.registerSummaries(m)
 
 	if h.kubectlEnabled {
 		h.registerKubectl(m)
@@ -0,0 +1,453 @@
+package tool
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+type serviceTrace struct {
+	Service   string             `json:"service"`
+	Type      corev1.ServiceType `json:"type"`
+	ClusterIP string             `json:"clusterIP,omitempty"`
+	Selector  map[string]string  `json:"selector,omitempty"`
+	Ports     []portTrace        `json:"ports"`
+	Pods      []podTrace         `json:"pods"`
+	Endpoints []endpointTrace    `json:"endpoints"`
+	Nodes     []nodeTrace        `json:"nodes"`
+	Issues    []string           `json:"issues"`
+}
+
+type portTrace struct {
+	Name       string   `json:"name,omitempty"`
+	Port       int32    `json:"port"`
+	Protocol   string   `json:"protocol"`
+	TargetPort string   `json:"targetPort"`
+	Matches    []string `json:"matchedContainerPorts"`
+}
+
+type podTrace struct {
+	Name  string `json:"name"`
+	Phase string `json:"phase"`
+	Ready bool   `json:"ready"`
+	Node  string `json:"node,omitempty"`
+}
+
+type endpointTrace struct {
+	Address     string `json:"address"`
+	Pod         string `json:"pod,omitempty"`
+	Node        string `json:"node,omitempty"`
+	Ready       bool   `json:"ready"`
+	Serving     bool   `json:"serving"`
+	Terminating bool   `json:"terminating"`
+}
+
+type nodeTrace struct {
+	Name  string `json:"name"`
+	Ready bool   `json:"ready"`
+}
+
+type ingressTrace struct {
+	Ingress      string          `json:"ingress"`
+	IngressClass string          `json:"ingressClass,omitempty"`
+	Backends     []backendTrace  `json:"backends"`
+	Services     []*serviceTrace `json:"services"`
+	Issues       []string        `json:"issues"`
+}
+
+// traceResult is the structured output of trace_service: the trace of a
+// Service or of an Ingress, depending on the kind traced.
+type traceResult struct {
+	Kind    string        `json:"kind"`
+	Service *serviceTrace `json:"serviceTrace,omitempty"`
+	Ingress *ingressTrace `json:"ingressTrace,omitempty"`
+}
+
+type backendTrace struct {
+	Host    string `json:"host,omitempty"`
+	Path    string `json:"path,omitempty"`
+	Service string `json:"service"`
+	Port    string `json:"port"`
+}
+
+func (h *Handler) registerTrace(m *server.MCPServer) {
+	h.addTool(m, mcp.NewTool("trace_service",
+		mcp.WithDescription("Trace a Service or Ingress through EndpointSlices to pods and nodes, reporting endpoint readiness, service ports matched against container ports, and misconfigurations such as selector mismatches, unexposed targetPorts, no ready endpoints and Ingress backends pointing at missing services"),
+		mcp.WithString("kind",
+			mcp.Description("Kind of object to trace"),
+			mcp.Enum("service", "ingress"),
+			mcp.DefaultString("service"),
+		),
+		mcp.WithString("name",
+			mcp.Description("Name of the Service or Ingress"),
+			mcp.Required(),
+		),
+		mcp.WithString("namespace",
+			mcp.Description("Namespace of the object (defaults to the current context's namespace)"),
+		),
+		mcp.WithOutputSchema[traceResult](),
+	), mcp.NewTypedToolHandler[TraceServiceArgs](h.traceServiceHandler()))
+}
+
+type TraceServiceArgs struct {
+	Kind      string `json:"kind,omitempty"`
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+func (h *Handler) traceServiceHandler() mcp.TypedToolHandlerFunc[TraceServiceArgs] {
+	return func(
+		ctx context.Context,
+		req mcp.CallToolRequest,
+		args TraceServiceArgs,
+	) (*mcp.CallToolResult, error) {
+		namespace := args.Namespace
+		if namespace == "" {
+			namespace = h.defaultNamespace
+		}
+
+		var trace traceResult
+		var err error
+		switch args.Kind {
+		case "", "service":
+			trace.Kind = "service"
+			trace.Service, err = h.traceService(ctx, namespace, args.Name)
+		case "ingress":
+			trace.Kind = "ingress"
+			trace.Ingress, err = h.traceIngress(ctx, namespace, args.Name)
+		default:
+			return mcp.NewToolResultError(fmt.Sprintf("unsupported kind %q, expected service or ingress", args.Kind)), nil
+		}
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("trace failed", err), nil
+		}
+
+		result, err := structuredResult(trace)
+		if err != nil {
+			return mcp.NewToolResultErrorFromErr("failed to marshal trace", err), nil
+		}
+		return result, nil
+	}
+}
+
+func (h *Handler) traceService(ctx context.Context, namespace, name string) (*serviceTrace, error) {
+	svc, err := h.client.CoreV1().Services(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get service %s/%s: %w", namespace, name, err)
+	}
+
+	trace := &serviceTrace{
+		Service:   namespace + "/" + name,
+		Type:      svc.Spec.Type,
+		ClusterIP: svc.Spec.ClusterIP,
+		Selector:  svc.Spec.Selector,
+		Ports:     []portTrace{},
+		Pods:      []podTrace{},
+		Endpoints: []endpointTrace{},
+		Nodes:     []nodeTrace{},
+		Issues:    []string{},
+	}
+
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		trace.Issues = append(trace.Issues, fmt.Sprintf("ExternalName service resolves to %s; there are no endpoints to trace", svc.Spec.ExternalName))
+		return trace, nil
+	}
+
+	var pods []corev1.Pod
+	if len(svc.Spec.Selector) == 0 {
+		trace.Issues = append(trace.Issues, "service has no selector; its EndpointSlices must be managed manually")
+	} else {
+		list, err := h.client.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods in namespace %s: %w", namespace, err)
+		}
+		pods, trace.Issues = selectPods(svc.Spec.Selector, list.Items, trace.Issues)
+		for _, pod := range pods {
+			trace.Pods = append(trace.Pods, podTrace{
+				Name:  pod.Name,
+				Phase: string(pod.Status.Phase),
+				Ready: podReady(&pod),
+				Node:  pod.Spec.NodeName,
+			})
+		}
+	}
+
+	trace.Ports, trace.Issues = matchServicePorts(svc, pods, trace.Issues)
+
+	slices, err := h.client.DiscoveryV1().EndpointSlices(namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: discoveryv1.LabelServiceName + "=" + name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpointslices for service %s/%s: %w", namespace, name, err)
+	}
+
+	nodeNames := map[string]bool{}
+	readyEndpoints := 0
+	for _, slice := range slices.Items {
+		for _, ep := range slice.Endpoints {
+			trace.Endpoints = append(trace.Endpoints, endpointFromSlice(ep))
+			if ep.Conditions.Ready == nil || *ep.Conditions.Ready {
+				readyEndpoints++
+			}
+			if ep.NodeName != nil {
+				nodeNames[*ep.NodeName] = true
+			}
+		}
+	}
+	for _, pod := range pods {
+		if pod.Spec.NodeName != "" {
+			nodeNames[pod.Spec.NodeName] = true
+		}
+	}
+
+	if len(trace.Endpoints) == 0 {
+		trace.Issues = append(trace.Issues, "service has no endpoints")
+	} else if readyEndpoints == 0 {
+		trace.Issues = append(trace.Issues, "service has no ready endpoints; check pod readiness probes")
+	}
+
+	for _, nodeName := range sortedKeys(nodeNames) {
+		node := nodeTrace{Name: nodeName}
+		n, err := h.client.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+		if err != nil {
+			trace.Issues = append(trace.Issues, fmt.Sprintf("could not check node %s: %v", nodeName, err))
+			trace.Nodes = append(trace.Nodes, node)
+			continue
+		}
+		for _, c := range n.Status.Conditions {
+			if c.Type == corev1.NodeReady {
+				node.Ready = c.Status == corev1.ConditionTrue
+			}
+		}
+		if !node.Ready {
+			trace.Issues = append(trace.Issues, fmt.Sprintf("node %s hosting endpoints is not ready", nodeName))
+		}
+		trace.Nodes = append(trace.Nodes, node)
+	}
+
+	return trace, nil
+}
+
+func (h *Handler) traceIngress(ctx context.Context, namespace, name string) (*ingressTrace, error) {
+	ing, err := h.client.NetworkingV1().Ingresses(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ingress %s/%s: %w", namespace, name, err)
+	}
+
+	trace := &ingressTrace{
+		Ingress:  namespace + "/" + name,
+		Backends: []backendTrace{},
+		Services: []*serviceTrace{},
+		Issues:   []string{},
+	}
+	if ing.Spec.IngressClassName != nil {
+		trace.IngressClass = *ing.Spec.IngressClassName
+	}
+
+	var backends []backendTrace
+	if b := ing.Spec.DefaultBackend; b != nil && b.Service != nil {
+		backends = append(backends, backendTrace{Path: "(default)", Service: b.Service.Name, Port: backendPort(b.Service.Port)})
+	}
+	for _, rule := range ing.Spec.Rules {
+		if rule.HTTP == nil {
+			continue
+		}
+		for _, path := range rule.HTTP.Paths {
+			if path.Backend.Service == nil {
+				continue
+			}
+			backends = append(backends, backendTrace{
+				Host:    rule.Host,
+				Path:    path.Path,
+				Service: path.Backend.Service.Name,
+				Port:    backendPort(path.Backend.Service.Port),
+			})
+		}
+	}
+	if len(backends) == 0 {
+		trace.Issues = append(trace.Issues, "ingress has no service backends")
+	}
+	trace.Backends = append(trace.Backends, backends...)
+
+	traced := map[string]*serviceTrace{}
+	for _, b := range backends {
+		svcTrace, ok := traced[b.Service]
+		if !ok {
+			svcTrace, err = h.traceService(ctx, namespace, b.Service)
+			if apierrors.IsNotFound(err) {
+				trace.Issues = append(trace.Issues, fmt.Sprintf("backend %s%s points at missing service %s", b.Host, b.Path, b.Service))
+				traced[b.Service] = nil
+				continue
+			}
+			if err != nil {
+				return nil, err
+			}
+			traced[b.Service] = svcTrace
+			trace.Services = append(trace.Services, svcTrace)
+		}
+		if svcTrace == nil {
+			continue
+		}
+		if !servicePortExists(svcTrace.Ports, b.Port) {
+			trace.Issues = append(trace.Issues, fmt.Sprintf("backend %s%s uses port %s which service %s does not expose", b.Host, b.Path, b.Port, b.Service))
+		}
+	}
+
+	return trace, nil
+}
+
+// selectPods returns the pods matching the selector. When none match, it
+// reports pods that match only part of the selector, which usually points at
+// a typo in a label.
+func selectPods(selector map[string]string, pods []corev1.Pod, issues []string) ([]corev1.Pod, []string) {
+	sel := labels.SelectorFromSet(selector)
+
+	var matched []corev1.Pod
+	for _, pod := range pods {
+		if sel.Matches(labels.Set(pod.Labels)) {
+			matched = append(matched, pod)
+		}
+	}
+	if len(matched) > 0 {
+		return matched, issues
+	}
+
+	issues = append(issues, fmt.Sprintf("selector %s matches no pods", sel.String()))
+	for _, pod := range pods {
+		var missing []string
+		for key, value := range selector {
+			if pod.Labels[key] != value {
+				missing = append(missing, key+"="+value)
+			}
+		}
+		if len(missing) < len(selector) {
+			sort.Strings(missing)
+			issues = append(issues, fmt.Sprintf("pod %s matches part of the selector but not %v", pod.Name, missing))
+		}
+	}
+	return nil, issues
+}
+
+// matchServicePorts resolves every service targetPort against the container
+// ports of the selected pods.
+func matchServicePorts(svc *corev1.Service, pods []corev1.Pod, issues []string) ([]portTrace, []string) {
+	ports := []portTrace{}
+	for _, sp := range svc.Spec.Ports {
+		target := sp.TargetPort
+		if target.Type == intstr.Int && target.IntVal == 0 {
+			target = intstr.FromInt32(sp.Port)
+		}
+
+		port := portTrace{
+			Name:       sp.Name,
+			Port:       sp.Port,
+			Protocol:   string(sp.Protocol),
+			TargetPort: target.String(),
+			Matches:    []string{},
+		}
+		declared := false
+		for _, pod := range pods {
+			for _, c := range pod.Spec.Containers {
+				for _, cp := range c.Ports {
+					declared = true
+					if containerPortMatches(cp, target, sp.Protocol) {
+						port.Matches = append(port.Matches, fmt.Sprintf("%s/%s:%d", pod.Name, c.Name, cp.ContainerPort))
+					}
+				}
+			}
+		}
+
+		if len(pods) > 0 && len(port.Matches) == 0 {
+			switch {
+			case target.Type == intstr.String:
+				issues = append(issues, fmt.Sprintf("targetPort %q of port %d is not a named port of any selected container", target.StrVal, sp.Port))
+			case declared:
+				issues = append(issues, fmt.Sprintf("targetPort %d of port %d is not exposed by any selected container", target.IntVal, sp.Port))
+			}
+		}
+		ports = append(ports, port)
+	}
+	return ports, issues
+}
+
+func containerPortMatches(cp corev1.ContainerPort, target intstr.IntOrString, protocol corev1.Protocol) bool {
+	cpProtocol := cp.Protocol
+	if cpProtocol == "" {
+		cpProtocol = corev1.ProtocolTCP
+	}
+	if protocol == "" {
+		protocol = corev1.ProtocolTCP
+	}
+	if cpProtocol != protocol {
+		return false
+	}
+	if target.Type == intstr.String {
+		return cp.Name == target.StrVal
+	}
+	return cp.ContainerPort == target.IntVal
+}
+
+func servicePortExists(ports []portTrace, port string) bool {
+	for _, p := range ports {
+		if p.Name == port || strconv.Itoa(int(p.Port)) == port {
+			return true
+		}
+	}
+	return false
+}
+
+func backendPort(port networkingv1.ServiceBackendPort) string {
+	if port.Name != "" {
+		return port.Name
+	}
+	return strconv.Itoa(int(port.Number))
+}
+
+func endpointFromSlice(ep discoveryv1.Endpoint) endpointTrace {
+	trace := endpointTrace{
+		Ready:   ep.Conditions.Ready == nil || *ep.Conditions.Ready,
+		Serving: ep.Conditions.Serving == nil || *ep.Conditions.Serving,
+	}
+	if ep.Conditions.Terminating != nil {
+		trace.Terminating = *ep.Conditions.Terminating
+	}
+	if len(ep.Addresses) > 0 {
+		trace.Address = ep.Addresses[0]
+	}
+	if ep.TargetRef != nil && ep.TargetRef.Kind == "Pod" {
+		trace.Pod = ep.TargetRef.Name
+	}
+	if ep.NodeName != nil {
+		trace.Node = *ep.NodeName
+	}
+	return trace
+}
+
+func podReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,69 @@
+package tool
+
+import (
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func TestSelectPods(t *testing.T) {
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-1", Labels: map[string]string{"app": "web", "tier": "frontend"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "web-2", Labels: map[string]string{"app": "web", "tier": "front"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "db-1", Labels: map[string]string{"app": "db"}}},
+	}
+
+	matched, issues := selectPods(map[string]string{"app": "web", "tier": "frontend"}, pods, nil)
+	if len(matched) != 1 || matched[0].Name != "web-1" || len(issues) != 0 {
+		t.Errorf("selectPods() = %v, %v, want web-1 without issues", matched, issues)
+	}
+
+	matched, issues = selectPods(map[string]string{"app": "web", "tier": "backend"}, pods, nil)
+	want := []string{
+		"selector app=web,tier=backend matches no pods",
+		"pod web-1 matches part of the selector but not [tier=backend]",
+		"pod web-2 matches part of the selector but not [tier=backend]",
+	}
+	if len(matched) != 0 || !reflect.DeepEqual(issues, want) {
+		t.Errorf("selectPods() = %v, %v, want no pods and %v", matched, issues, want)
+	}
+}
+
+func TestMatchServicePorts(t *testing.T) {
+	pods := []corev1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-1"},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Name:  "app",
+			Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: 8080}},
+		}}},
+	}}
+
+	tests := []struct {
+		name        string
+		port        corev1.ServicePort
+		wantMatches []string
+		wantIssues  int
+	}{
+		{"named target", corev1.ServicePort{Port: 80, TargetPort: intstr.FromString("http")}, []string{"web-1/app:8080"}, 0},
+		{"numeric target", corev1.ServicePort{Port: 80, TargetPort: intstr.FromInt32(8080)}, []string{"web-1/app:8080"}, 0},
+		{"defaults to port", corev1.ServicePort{Port: 8080}, []string{"web-1/app:8080"}, 0},
+		{"unknown name", corev1.ServicePort{Port: 80, TargetPort: intstr.FromString("web")}, []string{}, 1},
+		{"unexposed number", corev1.ServicePort{Port: 80, TargetPort: intstr.FromInt32(9090)}, []string{}, 1},
+		{"protocol mismatch", corev1.ServicePort{Port: 80, Protocol: corev1.ProtocolUDP, TargetPort: intstr.FromInt32(8080)}, []string{}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := &corev1.Service{Spec: corev1.ServiceSpec{Ports: []corev1.ServicePort{tt.port}}}
+			ports, issues := matchServicePorts(svc, pods, nil)
+			if !reflect.DeepEqual(ports[0].Matches, tt.wantMatches) {
+				t.Errorf("matchServicePorts() matches = %v, want %v", ports[0].Matches, tt.wantMatches)
+			}
+			if len(issues) != tt.wantIssues {
+				t.Errorf("matchServicePorts() issues = %v, want %d", issues, tt.wantIssues)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Package toolset selects which tools and resources a server exposes, from a
+// named profile and lists of enabled and disabled name patterns.
+package toolset
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// readOnlyTools are the tools that never change the cluster.
+var readOnlyTools = []string{
+	"api_resources",
+	"can_i",
+	"cluster_health",
+	"count_pods",
+	"cronjob_runs",
+	"deprecated_apis",
+	"diff_manifest",
+	"explain",
+	"helm_*",
+	"kubectl_describe",
+	"kubectl_get",
+	"kubectl_logs",
+	"owner_tree",
+	"rbac_*",
+	"security_scan",
+	"summarize_resources",
+	"trace_service",
+}
+
+// operatorTools are the tools that change workloads without deleting them or
+// running arbitrary kubectl commands.
+var operatorTools = []string{
+	"apply_manifest",
+	"create_job_from_cronjob",
+	"kubectl_annotate",
+	"kubectl_apply",
+	"kubectl_create",
+	"kubectl_label",
+	"patch_resource",
+	"resume_cronjob",
+	"suspend_cronjob",
+}
+
+// Profiles maps each profile name to the tool name patterns it enables.
+var Profiles = map[string][]string{
+	"readonly": readOnlyTools,
+	"operator": append(append([]string{}, readOnlyTools...), operatorTools...),
+	"admin":    {"*"},
+}
+
+// Filter decides which tools and resources are exposed. Patterns are matched
+// with path.Match, so "helm_*" matches every Helm tool and "k8s://cluster/*"
+// every cluster-scoped resource.
+type Filter struct {
+	profile string
+	// profileTools are the patterns enabled by the profile.
+	profileTools []string
+	enabled      []string
+	disabled     []string
+}
+
+// New returns a filter enabling the tools of the profile and the enabled
+// patterns, minus the disabled patterns. Without a profile or enabled patterns
+// all tools are enabled.
+func New(profile string, enabled, disabled []string) (*Filter, error) {
+	f := &Filter{profile: profile, enabled: enabled, disabled: disabled}
+	if profile != "" {
+		patterns, ok := Profiles[profile]
+		if !ok {
+			return nil, fmt.Errorf("unknown profile %q (expected one of %s)", profile, strings.Join(ProfileNames(), ", "))
+		}
+		f.profileTools = patterns
+	}
+
+	for _, pattern := range append(append([]string{}, enabled...), disabled...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return f, nil
+}
+
+// ProfileNames returns the names of the profiles, sorted.
+func ProfileNames() []string {
+	names := make([]string, 0, len(Profiles))
+	for name := range Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// AllowsTool reports whether the named tool is exposed. A nil filter allows
+// every tool.
+func (f *Filter) AllowsTool(name string) bool {
+	if f == nil {
+		return true
+	}
+	if (f.profile != "" || len(f.enabled) > 0) && !matchAny(f.profileTools, name) && !matchAny(f.enabled, name) {
+		return false
+	}
+	return !matchAny(f.disabled, name)
+}
+
+// AllowsResource reports whether the resource or resource template with the
+// URI is exposed. Resources are read-only, so the profile and enabled patterns
+// don't restrict them; only disabled patterns matching the URI hide them.
+func (f *Filter) AllowsResource(uri string) bool {
+	if f == nil {
+		return true
+	}
+	return !matchAny(f.disabled, uri)
+}
+
+// Instructions describes the active profile and patterns for the server
+// instructions, or returns an empty string when everything is enabled.
+func (f *Filter) Instructions() string {
+	if f == nil {
+		return ""
+	}
+	var lines []string
+	if f.profile != "" {
+		lines = append(lines, fmt.Sprintf("Active tool profile: %s.", f.profile))
+		if f.profile == "readonly" {
+			lines = append(lines, "Tools that change the cluster are not available; suggest the commands to run instead.")
+		}
+	}
+	if len(f.enabled) > 0 {
+		prefix := "Enabled tools"
+		if f.profile != "" {
+			prefix = "Additionally enabled tools"
+		}
+		lines = append(lines, fmt.Sprintf("%s: %s.", prefix, strings.Join(f.enabled, ", ")))
+	}
+	if len(f.disabled) > 0 {
+		lines = append(lines, fmt.Sprintf("Disabled tools and resources: %s.", strings.Join(f.disabled, ", ")))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
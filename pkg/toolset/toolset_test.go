@@ -0,0 +1,123 @@
+package toolset
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFilterAllowsTool(t *testing.T) {
+	tests := []struct {
+		name     string
+		profile  string
+		enabled  []string
+		disabled []string
+		allowed  []string
+		denied   []string
+	}{
+		{
+			name:    "no selection allows everything",
+			allowed: []string{"count_pods", "kubectl_delete", "kubectl_generic"},
+		},
+		{
+			name:    "readonly",
+			profile: "readonly",
+			allowed: []string{"kubectl_get", "helm_values", "rbac_who_can", "count_pods"},
+			denied:  []string{"kubectl_apply", "kubectl_delete", "apply_manifest", "kubectl_generic"},
+		},
+		{
+			name:    "operator",
+			profile: "operator",
+			allowed: []string{"kubectl_get", "kubectl_apply", "suspend_cronjob"},
+			denied:  []string{"kubectl_delete", "kubectl_generic"},
+		},
+		{
+			name:    "admin",
+			profile: "admin",
+			allowed: []string{"kubectl_delete", "kubectl_generic"},
+		},
+		{
+			name:    "profile plus enabled",
+			profile: "readonly",
+			enabled: []string{"kubectl_generic"},
+			allowed: []string{"kubectl_get", "kubectl_generic"},
+			denied:  []string{"kubectl_delete"},
+		},
+		{
+			name:    "enabled only",
+			enabled: []string{"kubectl_*"},
+			allowed: []string{"kubectl_get", "kubectl_delete"},
+			denied:  []string{"count_pods", "helm_list"},
+		},
+		{
+			name:     "disabled wins",
+			profile:  "admin",
+			disabled: []string{"count_pods", "helm_*"},
+			allowed:  []string{"kubectl_delete"},
+			denied:   []string{"count_pods", "helm_list", "helm_values"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := New(tt.profile, tt.enabled, tt.disabled)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			for _, name := range tt.allowed {
+				if !f.AllowsTool(name) {
+					t.Errorf("AllowsTool(%q) = false, want true", name)
+				}
+			}
+			for _, name := range tt.denied {
+				if f.AllowsTool(name) {
+					t.Errorf("AllowsTool(%q) = true, want false", name)
+				}
+			}
+		})
+	}
+}
+
+func TestFilterAllowsResource(t *testing.T) {
+	f, err := New("readonly", []string{"kubectl_get"}, []string{"k8s://cluster/*", "k8s://*/configmaps"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	tests := map[string]bool{
+		"k8s://pods":                   true,
+		"k8s://{namespace}/pods":       true,
+		"k8s://cluster/nodes":          false,
+		"k8s://{namespace}/configmaps": false,
+	}
+	for uri, want := range tests {
+		if got := f.AllowsResource(uri); got != want {
+			t.Errorf("AllowsResource(%q) = %v, want %v", uri, got, want)
+		}
+	}
+}
+
+func TestNewErrors(t *testing.T) {
+	if _, err := New("superuser", nil, nil); err == nil || !strings.Contains(err.Error(), "admin, operator, readonly") {
+		t.Errorf("New() with unknown profile error = %v, want the known profiles listed", err)
+	}
+	if _, err := New("", []string{"kubectl_["}, nil); err == nil {
+		t.Error("New() with malformed pattern succeeded")
+	}
+}
+
+func TestFilterInstructions(t *testing.T) {
+	f, err := New("readonly", []string{"kubectl_generic"}, []string{"helm_*"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	want := "Active tool profile: readonly.\n" +
+		"Tools that change the cluster are not available; suggest the commands to run instead.\n" +
+		"Additionally enabled tools: kubectl_generic.\n" +
+		"Disabled tools and resources: helm_*."
+	if got := f.Instructions(); got != want {
+		t.Errorf("Instructions() = %q, want %q", got, want)
+	}
+
+	f, _ = New("", nil, nil)
+	if got := f.Instructions(); got != "" {
+		t.Errorf("Instructions() without a selection = %q, want empty", got)
+	}
+}